@@ -55,57 +55,229 @@
 //             asset.WithManifestLoader(Asset),
 //         )
 //
+//         // Load the manifest from any fs.FS, e.g. embed.FS; asset files (e.g. for SRI) are read from it as well.
+//         static, err = asset.NewStaticFromFS("/static/", distFS, "manifest.json", asset.WithUseMinified(true))
+//
 //         // There's also WithMappingBuilder option to create an asset mapper without
 //         // using the manifest file.
 //     }
+//
+// The loaded manifest can be served as JSON with ManifestHandler, e.g. at /manifest, and HealthCheckHandler,
+// e.g. served at /health for load balancers, reports whether the manifest is loaded:
+//
+//     http.Handle("/manifest", static.ManifestHandler())
+//     http.Handle("/health", static.HealthCheckHandler())
+//
+// Integrations with frameworks and features relying on third-party libraries, e.g. YAML manifests, live in
+// the sub-packages (chi, echo, fiber, gorilla, openapi, schema, yaml and zstd), so that this package depends
+// only on the standard library.
+//
+// Static is safe for concurrent use: the manifest can be replaced with SwapManifest or ReloadManifest while
+// templates are being rendered, as the mapping is guarded by sync.RWMutex (see WithManifestLock). Functions
+// attached with AttachFuncs should be set up before the instance is shared.
 package asset
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"html"
 	"html/template"
+	"io"
+	"io/fs"
 	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Static holds configurtion for the asset resolver. It should be created using NewStatic
 type Static struct {
-	urlPrefix      string
-	manifestPath   string
-	manifestLoader Loader
-	useMinified    bool
-	mapping        StaticMapper
-	mappingBuilder MappingBuilder
+	urlPrefix       string
+	manifestPath    string
+	manifestLoader  Loader
+	useMinified     bool
+	mapping         StaticMapper
+	mappingLock     sync.Locker
+	mappingRLock    readLocker
+	mappingBuilder  MappingBuilder
+	cdnRewriter     func(string) string
+	urlTransformer  func(string) string
+	annotateTags    bool
+	debugComments   bool
+	fsys            fs.FS
+	cdn             *cdnFailover
+	cdnPingPath     string
+	cdnPingTimeout  time.Duration
+	transforms      []manifestTransform
+	groups          []Group
+	groupBy         func(string) string
+	urlEncode       bool
+	errorHandler    func(http.ResponseWriter, *http.Request, error)
+	manifestScheme  ManifestScheme
+	updates         <-chan []byte
+	reloadCallbacks []func(error)
+	typedErrors     bool
+	setGlobal       bool
+	webpackManifest bool
+	stripExtension  bool
+	inferredExts    []string
+	hitCounter      func(string, bool)
+	minifiedOnly    bool
+	sizeLimit       int64
+	sizeLimitAction LimitAction
+	onError         func(error)
+	extraFuncs      template.FuncMap
+	disableEscaping bool
+	profiler        AssetProfiler
+	sriWorkers      int
+	sriCache        *sync.Map
+	trackUsage      bool
+	usage           *sync.Map
+	ttfbHintCount   int
+	assetHash       func([]byte) string
+	fuzzyMatch      bool
+	fuzzyThreshold  float64
+	outputFormat    OutputFormat
+	useSri          bool
+	sriAlgorithms   []string
+	warmProgress    func(int, int)
+	dedupTags       bool
+	rendered        *renderedTags
+	parent          *Static
+	decompress      func([]byte) ([]byte, error)
+	hints           []ResourceHint
+	strictMode      bool
+	fallback        func(string) string
+	cacheBustHeader string
+	manifestFormat  string
+	annotator       func(key, resolvedURL string) map[string]string
+	buildTime       time.Time
+	nonces          *nonceSet
+	refreshInterval time.Duration
+	refreshOnError  func(error)
+	refresher       *refresher
+	validators      []func(manifest interface{}) error
+	nonceFunc       func() string
+	scriptPrefix    string
+	linkPrefix      string
+	scriptAttrs     map[string]string
+	linkAttrs       map[string]string
+	extraManifests  []string
+	manifestParser  ManifestParser
+	keyNormalizer   func(string) string
+	pathRewrites    []func(string) string
+	optionErr       error
 }
 
 // NewStatic creates an instance of static, which can be then used to attach helper functions to templates.
 func NewStatic(urlPrefix string, manifestPath string, options ...optionSetter) (*Static, error) {
-	if !strings.HasSuffix(urlPrefix, "/") {
-		urlPrefix += "/"
-	}
 	static := &Static{
 		urlPrefix:      urlPrefix,
 		manifestPath:   manifestPath,
 		manifestLoader: ioutil.ReadFile,
+		manifestParser: JSONManifestParser{},
+		inferredExts:   defaultInferredExtensions,
+		ttfbHintCount:  defaultTTFBHintCount,
+		sriAlgorithms:  []string{defaultSriAlgorithm},
+		sriCache:       &sync.Map{},
+		usage:          &sync.Map{},
+		nonces:         newNonceSet(),
+		cdnPingPath:    defaultCDNPingPath,
+		cdnPingTimeout: defaultCDNPingTimeout,
 	}
 	for _, optionSetter := range options {
 		optionSetter(static)
 	}
+	if static.manifestScheme == ManifestSchemePush && static.updates == nil {
+		static.setOptionErr(errNoUpdateChan)
+	}
+	if _, isJSON := static.manifestParser.(JSONManifestParser); isJSON && static.manifestFormat == ManifestFormatYAML {
+		static.setOptionErr(errNoYAMLParser)
+	}
+	static.initLocks()
+	if static.optionErr != nil {
+		return nil, static.manifestError(static.optionErr)
+	}
+	if static.cdn != nil {
+		static.urlPrefix = static.cdn.selectOrigin(static.cdnPingPath, static.cdnPingTimeout)
+	}
+	if !strings.HasSuffix(static.urlPrefix, "/") {
+		static.urlPrefix += "/"
+	}
 	if static.mappingBuilder == nil {
 		static.mappingBuilder = func() (StaticMapper, error) {
-			return createMapping(static.manifestLoader, manifestPath, static.useMinified)
+			mapping, err := static.createMapping(static.manifestLoader, static.manifestPath)
+			if static.assetHash != nil && (static.manifestLoader == nil || errors.Is(err, fs.ErrNotExist)) {
+				return static.hashAssets()
+			}
+			return mapping, err
+		}
+	}
+	if len(static.extraManifests) > 0 {
+		static.mappingBuilder = static.mergingBuilder(static.mappingBuilder)
+	}
+	if static.mapping == nil {
+		mapping, err := static.mappingBuilder()
+		if err != nil {
+			return nil, static.manifestError(err)
 		}
+		static.mapping = mapping
+	}
+	if static.manifestScheme == ManifestSchemePush || static.refreshInterval > 0 {
+		static.refresher = &refresher{stop: make(chan struct{})}
+	}
+	if static.manifestScheme == ManifestSchemePush {
+		go static.receiveManifests()
+	}
+	if static.refreshInterval > 0 {
+		static.startRefreshing()
+	}
+	if static.setGlobal {
+		SetGlobal(static)
+	}
+	return static, nil
+}
+
+// NewStaticFromReader creates an instance of static with the manifest read from r, e.g. http.Response.Body.
+// r is read before the options are applied and closed if it implements io.Closer.
+func NewStaticFromReader(urlPrefix string, r io.Reader, options ...optionSetter) (*Static, error) {
+	content, err := ioutil.ReadAll(r)
+	if closer, ok := r.(io.Closer); ok {
+		closer.Close()
 	}
-	mapping, err := static.mappingBuilder()
 	if err != nil {
 		return nil, err
 	}
-	static.mapping = mapping
-	return static, nil
+	load := func(string) ([]byte, error) { return content, nil }
+	options = append(options[:len(options):len(options)], WithManifestLoader(load))
+	return NewStatic(urlPrefix, "", options...)
+}
+
+// NewStaticFromMap creates an instance of static with the manifest entries given as m, e.g. in tests or when
+// they're computed by the application, instead of loading the manifest.
+func NewStaticFromMap(urlPrefix string, m map[string]string, options ...optionSetter) (*Static, error) {
+	fromMap := func(st *Static) {
+		st.mappingBuilder = func() (StaticMapper, error) { return st.mappingFromMap(m), nil }
+	}
+	options = append(options[:len(options):len(options)], fromMap)
+	return NewStatic(urlPrefix, "", options...)
+}
+
+// NewStaticFromFS creates an instance of static with the manifest read from manifestPath in fsys, e.g.
+// embed.FS. Asset files, e.g. for WithUseSri, are read from fsys as well.
+func NewStaticFromFS(urlPrefix string, fsys fs.FS, manifestPath string, options ...optionSetter) (*Static, error) {
+	fromFS := func(st *Static) {
+		st.manifestLoader = func(name string) ([]byte, error) { return fs.ReadFile(fsys, name) }
+		st.fsys = fsys
+	}
+	options = append(options[:len(options):len(options)], fromFS)
+	return NewStatic(urlPrefix, manifestPath, options...)
 }
 
 // ScriptTag returns HTML script tag; path should point to an asset, by default a path on the disk
@@ -114,26 +286,253 @@ func NewStatic(urlPrefix string, manifestPath string, options ...optionSetter) (
 // attrs can be used to pass additional attributes to the tag. There must be an even numner of
 // attrs. Usually not used directly, but registered in tempalte via FuncMap.
 func (st *Static) ScriptTag(path string, attrs ...string) (template.HTML, error) {
-	defaultAttrMap := map[string]string{"type": "text/javascript"}
+	return st.scriptTag(path, "", "text/javascript", attrs)
+}
+
+// scriptTag returns script tag of the given type with the version, if not empty, added to the URL, and the boolean
+// attributes.
+func (st *Static) scriptTag(
+	path string, version string, scriptType string, attrs []string, boolAttrs ...string,
+) (template.HTML, error) {
+	defer st.profile(path)()
+	defaultAttrMap := map[string]string{"type": scriptType}
+	st.addBuildTimestamp(defaultAttrMap)
+	st.addNonce(defaultAttrMap)
 	attrMap, err := attrSliceToMap(attrs)
 	if err != nil {
-		return "", err
+		return "", st.assetError(path, err)
+	}
+	resolved, err := st.resolveTag(path)
+	if err != nil {
+		return "", st.assetError(path, err)
+	}
+	if err := st.addSRI(defaultAttrMap, resolved); err != nil {
+		return "", st.assetError(path, err)
+	}
+	assetURL := addVersion(st.prefixedURL(st.typePrefix(st.scriptPrefix), resolved), version)
+	updateMap(defaultAttrMap, st.scriptAttrs)
+	if st.annotator != nil {
+		updateMap(defaultAttrMap, st.annotator(path, assetURL))
 	}
 	updateMap(defaultAttrMap, attrMap)
-	defaultAttrMap["src"] = st.urlPrefix + st.mapping.Get(path)
-	return template.HTML(fmt.Sprintf(`<script %s></script>`, mapToAttrs(defaultAttrMap))), nil
+	st.recordNonce(defaultAttrMap["nonce"])
+	defaultAttrMap["src"] = assetURL
+	tag, err := st.formatScriptTag(defaultAttrMap, boolAttrs...)
+	if err != nil {
+		return "", st.assetError(path, err)
+	}
+	if !st.firstRender("script", path) {
+		return "", nil
+	}
+	return st.annotate(path, tag), nil
 }
 
 // LinkTag returns HTML script tag. See ScriptTag for additional information. Usually not used directly, but registered in tempalte via FuncMap.
 func (st *Static) LinkTag(path string, attrs ...string) (template.HTML, error) {
+	return st.linkTag(path, "", attrs)
+}
+
+// linkTag returns link tag with the version, if not empty, added to the URL.
+func (st *Static) linkTag(path string, version string, attrs []string) (template.HTML, error) {
+	defer st.profile(path)()
 	defaultAttrMap := map[string]string{"type": "text/css", "rel": "stylesheet"}
+	st.addBuildTimestamp(defaultAttrMap)
+	st.addNonce(defaultAttrMap)
 	attrMap, err := attrSliceToMap(attrs)
 	if err != nil {
-		return "", err
+		return "", st.assetError(path, err)
+	}
+	resolved, err := st.resolveTag(path)
+	if err != nil {
+		return "", st.assetError(path, err)
+	}
+	if err := st.addSRI(defaultAttrMap, resolved); err != nil {
+		return "", st.assetError(path, err)
+	}
+	assetURL := addVersion(st.prefixedURL(st.typePrefix(st.linkPrefix), resolved), version)
+	updateMap(defaultAttrMap, st.linkAttrs)
+	if st.annotator != nil {
+		updateMap(defaultAttrMap, st.annotator(path, assetURL))
 	}
 	updateMap(defaultAttrMap, attrMap)
-	defaultAttrMap["href"] = st.urlPrefix + st.mapping.Get(path)
-	return template.HTML(fmt.Sprintf(`<link %s/>`, mapToAttrs(defaultAttrMap))), nil
+	st.recordNonce(defaultAttrMap["nonce"])
+	defaultAttrMap["href"] = assetURL
+	tag, err := st.formatLinkTag(defaultAttrMap)
+	if err != nil {
+		return "", st.assetError(path, err)
+	}
+	if !st.firstRender("link", path) {
+		return "", nil
+	}
+	return st.annotate(path, tag), nil
+}
+
+// annotate wraps a tag in HTML comments naming the logical asset if annotations are enabled, and prepends
+// the comment describing its resolution with WithDebugHTMLComments.
+func (st *Static) annotate(path string, tag string) template.HTML {
+	if st.annotateTags {
+		tag = fmt.Sprintf(`<!-- asset: %s -->%s<!-- /asset -->`, html.EscapeString(path), tag)
+	}
+	if st.debugComments {
+		tag = st.debugComment(path) + tag
+	}
+	return template.HTML(tag)
+}
+
+// debugComment returns the HTML comment with the path, the value it's resolved to in the manifest, and
+// whether the minified variant is used.
+func (st *Static) debugComment(path string) string {
+	mapping := st.currentMapping()
+	comment := fmt.Sprintf("<!-- asset: %s → %s", html.EscapeString(path), html.EscapeString(mapping.Get(path)))
+	if sm, ok := mapping.(*staticMap); ok && sm.minified(path) {
+		comment += " (minified)"
+	}
+	return comment + " -->"
+}
+
+// URLFor returns the URL of an asset: the URL prefix followed by the path resolved through the mapping.
+// With WithWebpackManifest, absolute URLs found in the manifest are used without the prefix.
+// When a CDN rewriter is configured, it's applied to the result as the final step.
+func (st *Static) URLFor(path string) string {
+	return st.resolvedURL(st.resolve(path))
+}
+
+// GetURL returns the URL of an asset, e.g. for redirects or API responses. It's the same as URLFor.
+func (st *Static) GetURL(path string) string {
+	return st.URLFor(path)
+}
+
+// Has reports whether an asset is in the manifest, also under the minified name with WithUseMinified(true),
+// e.g. to include optional assets only if they're available.
+func (st *Static) Has(path string) bool {
+	return st.currentMapping().Has(path)
+}
+
+// Keys returns the sorted paths of all the assets in the manifest, e.g. to build prefetch lists.
+func (st *Static) Keys() []string {
+	return st.currentMapping().Keys()
+}
+
+// Resolve returns the path an asset is resolved to, without the URL prefix, and whether it's resolved to
+// the minified variant with WithUseMinified(true).
+func (st *Static) Resolve(path string) (string, bool) {
+	resolved := st.resolve(path)
+	sm, ok := st.currentMapping().(*staticMap)
+	return resolved, ok && sm.minified(path)
+}
+
+// MustGetURL returns the URL of an asset like GetURL, but panics with WithStrictMode if the asset isn't
+// in the manifest.
+func (st *Static) MustGetURL(path string) string {
+	resolved, err := st.resolveTag(path)
+	if err != nil {
+		panic(err)
+	}
+	return st.resolvedURL(resolved)
+}
+
+// resolvedURL returns the URL of an already resolved path.
+func (st *Static) resolvedURL(resolved string) string {
+	return st.prefixedURL(st.urlPrefix, resolved)
+}
+
+// prefixedURL returns the URL of an already resolved path with the given URL prefix.
+func (st *Static) prefixedURL(urlPrefix string, resolved string) string {
+	if st.urlTransformer != nil {
+		return st.urlTransformer(resolved)
+	}
+	if st.webpackManifest && isAbsoluteURL(resolved) {
+		urlPrefix = ""
+	}
+	if st.urlEncode {
+		resolved = encodeResolved(resolved)
+	}
+	assetURL := urlPrefix + resolved
+	if st.cdnRewriter != nil {
+		assetURL = st.cdnRewriter(assetURL)
+	}
+	return assetURL
+}
+
+// resolve returns the path resolved through the mapping, reporting the lookup to the cache hit counter.
+func (st *Static) resolve(path string) string {
+	resolved, _ := st.resolveHit(path)
+	return resolved
+}
+
+// resolveHit resolves path and reports whether it was found in the manifest. Missing paths are resolved
+// with the function provided by WithFallbackFunc, if any.
+func (st *Static) resolveHit(path string) (string, bool) {
+	mapping := st.currentMapping()
+	resolved := mapping.Get(path)
+	hit := mapping.Has(path)
+	st.countUsage(path)
+	if st.hitCounter != nil {
+		st.hitCounter(path, hit)
+	}
+	if st.fuzzyMatch && !hit {
+		st.suggest(path)
+	}
+	if !hit && st.fallback != nil {
+		resolved = st.fallback(path)
+	}
+	return resolved, hit
+}
+
+// resolveTag resolves the path of an asset rendered as a tag, which with WithStrictMode must be in the manifest.
+func (st *Static) resolveTag(path string) (string, error) {
+	resolved, hit := st.resolveHit(path)
+	// with WithMinifiedFallback(false) assets without the minified variant are resolved to an empty path
+	if st.strictMode && (!hit || resolved == "") {
+		return "", fmt.Errorf("%w: %s", ErrAssetNotFound, path)
+	}
+	return resolved, nil
+}
+
+var fingerprintRegexp = regexp.MustCompile(`-([0-9a-fA-F]{8,})\.[^./]+$`)
+
+// AssetFingerprint returns the fingerprint embedded in the name of the file the path is resolved to, e.g.
+// "da89a0c4" for "dist/app-da89a0c4.js": a dash-separated hex segment of at least 8 characters right before
+// the extension. Returns false if there's no such segment.
+func (st *Static) AssetFingerprint(path string) (string, bool) {
+	match := fingerprintRegexp.FindStringSubmatch(stripQuery(st.resolve(path)))
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// stripQuery returns a resolved path without the query string.
+func stripQuery(resolved string) string {
+	if i := strings.Index(resolved, "?"); i >= 0 {
+		return resolved[:i]
+	}
+	return resolved
+}
+
+// encodeResolved percent-encodes every segment of a resolved path and every key and value of its query string.
+func encodeResolved(resolved string) string {
+	resolvedPath, query := resolved, ""
+	if i := strings.Index(resolved, "?"); i >= 0 {
+		resolvedPath, query = resolved[:i], resolved[i+1:]
+	}
+	segments := strings.Split(resolvedPath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	encoded := strings.Join(segments, "/")
+	if query == "" {
+		return encoded
+	}
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		pair := strings.SplitN(param, "=", 2)
+		for j, part := range pair {
+			pair[j] = url.QueryEscape(part)
+		}
+		params[i] = strings.Join(pair, "=")
+	}
+	return encoded + "?" + strings.Join(params, "&")
 }
 
 // Static returns URL prefix for static assets. Mainly intended to be used for image files etc. Usually not used directly, but registered in tempalte via FuncMap.
@@ -146,14 +545,150 @@ func (st *Static) Attach(tmpl *template.Template) {
 	tmpl.Funcs(st.FuncMap())
 }
 
+// Template returns a new, empty template with the helper functions attached, ready to be used e.g. with
+// ParseGlob: template.Must(static.Template().ParseGlob("views/*.html")). A new template is returned every call.
+func (st *Static) Template() *template.Template {
+	return template.New("").Funcs(st.FuncMap())
+}
+
+// RenderTemplate parses tmplStr as an HTML template with the helper functions attached and executes it
+// with data. Useful for rendering small snippets, e.g. in middleware, without defining named templates.
+func (st *Static) RenderTemplate(tmplStr string, data interface{}) (template.HTML, error) {
+	tmpl, err := st.Template().Parse(tmplStr)
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return template.HTML(out.String()), nil
+}
+
 // FuncMap returns template.FuncMap that can be used to attach go-asset-helper functions
-// to a template.
+// to a template. Functions added with AttachFuncs are included.
 func (st *Static) FuncMap() template.FuncMap {
-	return map[string]interface{}{
-		"scripttag": st.ScriptTag,
-		"linktag":   st.LinkTag,
-		"static":    st.Static,
+	funcMap := map[string]interface{}{
+		"scripttag":         st.ScriptTag,
+		"modulescripttag":   st.ModuleScriptTag,
+		"nomodulescripttag": st.NomoduleScriptTag,
+		"inlinescripttag":   st.InlineScriptTag,
+		"linktag":           st.LinkTag,
+		"inlinelinktag":     st.InlineLinkTag,
+		"linkmanifesttag":   st.LinkManifestTag,
+		"appletouchicontag": st.AppleTouchIconTag,
+		"favicontag":        st.FaviconTag,
+		"preloadtag":        st.PreloadTag,
+		"modulepreloadtag":  st.ModulePreloadTag,
+		"preconnecttag":     st.PreconnectTag,
+		"dnsprefetchtag":    st.DNSPrefetchTag,
+		"mediatag":          st.MediaTag,
+		"imgtag":            st.ImgTag,
+		"static":            st.Static,
+
+		"scripttags":      st.ScriptTags,
+		"linktags":        st.LinkTags,
+		"groupscripttags": st.GroupScriptTags,
+		"grouplinktags":   st.GroupLinkTags,
+		"emithead":        st.EmitHTMLHead,
+	}
+	for name, fn := range st.extraFuncs {
+		funcMap[name] = fn
+	}
+	return funcMap
+}
+
+// AllRegisteredFunctions returns the sorted names of all the template functions registered by FuncMap,
+// not including the ones added with AttachFuncs.
+func AllRegisteredFunctions() []string {
+	names := []string{}
+	for name := range (&Static{}).FuncMap() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AttachFuncs adds funcs to the functions returned by FuncMap and attached by Attach, replacing the ones
+// with the same names. Returns st for chaining, e.g. static.AttachFuncs(funcs).Attach(tmpl).
+func (st *Static) AttachFuncs(funcs template.FuncMap) *Static {
+	if st.extraFuncs == nil {
+		st.extraFuncs = template.FuncMap{}
+	}
+	for name, fn := range funcs {
+		st.extraFuncs[name] = fn
+	}
+	return st
+}
+
+// ToGinH returns the template functions as a map[string]interface{}, which is assignable to gin.H
+// without importing Gin.
+func (st *Static) ToGinH() map[string]interface{} {
+	return st.FuncMap()
+}
+
+// SwapManifest atomically replaces the mapping used to resolve assets. It's safe to call while
+// templates are being rendered. The manifests given with WithAdditionalManifests are merged into mapping;
+// if that fails, the error is passed to the WithOnError callback and the current mapping is kept.
+func (st *Static) SwapManifest(mapping StaticMapper) {
+	merged, err := st.mergeAdditionalManifests(mapping)
+	if err != nil {
+		st.reportError(st.manifestError(err))
+		return
+	}
+	st.replaceMapping(merged)
+}
+
+// replaceMapping atomically replaces the mapping used to resolve assets.
+func (st *Static) replaceMapping(mapping StaticMapper) {
+	if st.parent != nil {
+		st.parent.replaceMapping(mapping)
+		return
+	}
+	st.mappingLock.Lock()
+	defer st.mappingLock.Unlock()
+	st.mapping = mapping
+}
+
+func (st *Static) currentMapping() StaticMapper {
+	if st.parent != nil {
+		return st.parent.currentMapping()
+	}
+	st.mappingRLock.RLock()
+	defer st.mappingRLock.RUnlock()
+	return st.mapping
+}
+
+// Clone returns a copy of st sharing its manifest, so that manifest updates apply to both, and caches.
+// The clone tracks the nonces returned by CSPNonces and, with WithTagDeduplication, rendered tags separately,
+// so it can be created for every rendered page.
+func (st *Static) Clone() *Static {
+	clone := *st
+	if st.parent == nil {
+		clone.parent = st
+	}
+	if st.dedupTags {
+		clone.rendered = newRenderedTags()
 	}
+	clone.nonces = newNonceSet()
+	clone.extraFuncs = nil
+	clone.AttachFuncs(st.extraFuncs)
+	return &clone
+}
+
+// CloneWithPrefix returns a shallow copy of st using newPrefix as the URL prefix, e.g. for tenants sharing the
+// manifest, but served from different CDN paths. Unlike Clone, everything else is shared with st, including
+// the tags rendered with WithTagDeduplication.
+func (st *Static) CloneWithPrefix(newPrefix string) *Static {
+	clone := *st
+	if st.parent == nil {
+		clone.parent = st
+	}
+	if !strings.HasSuffix(newPrefix, "/") {
+		newPrefix += "/"
+	}
+	clone.urlPrefix = newPrefix
+	return &clone
 }
 
 // StaticMapper is an interface for mapping between asset paths and references to be put
@@ -161,38 +696,101 @@ func (st *Static) FuncMap() template.FuncMap {
 type StaticMapper interface {
 	// Get returns reference to an specified as a path.
 	Get(string) string
+	// Has reports whether there's a reference for an asset specified as a path.
+	Has(string) bool
+	// Keys returns the sorted paths of all the assets with references.
+	Keys() []string
 }
 
 // MappingBuilder is a function that produces StaticMapper instances
 type MappingBuilder func() (StaticMapper, error)
 
 type staticMap struct {
-	innerMap    map[string]interface{}
-	useMinified bool
+	innerMap     map[string]string
+	useMinified  bool
+	extensions   []string
+	minifiedOnly bool
+	linked       map[string][]string
+	normalize    func(string) string
+	rewrite      func(string) string
+}
+
+// keys returns the manifest keys in no particular order.
+func (sm *staticMap) keys() []string {
+	keys := make([]string, 0, len(sm.innerMap))
+	for key := range sm.innerMap {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (sm staticMap) Keys() []string {
+	keys := append([]string(nil), sm.keys()...)
+	sort.Strings(keys)
+	return keys
 }
 
 func (sm staticMap) Get(name string) string {
-	if sm.useMinified {
-		minifiedName := toMinifiedName(name)
-		if value, ok := getStringFromMap(sm.innerMap, minifiedName); ok {
-			return value
+	if value, ok := sm.find(name); ok {
+		return value
+	}
+	return name
+}
+
+func (sm staticMap) Has(name string) bool {
+	_, ok := sm.find(name)
+	return ok
+}
+
+// minified reports whether name is resolved through the minified variant of the key.
+func (sm staticMap) minified(name string) bool {
+	for _, candidate := range sm.candidates(name) {
+		if _, ok := sm.lookup(candidate); ok {
+			_, ok := sm.innerMap[toMinifiedName(candidate)]
+			return sm.useMinified && ok
 		}
 	}
-	value, _ := getStringFromMap(sm.innerMap, name)
-	return value
+	return false
 }
 
-func getStringFromMap(amap map[string]interface{}, defaultValue string) (string, bool) {
-	value, ok := amap[defaultValue]
-	if ok {
-		switch val := value.(type) {
-		case string:
-			return val, true
-		default:
-			return defaultValue, false
+// candidates returns the keys name is looked up with: changed with WithPathPrefix and WithPathStrip, normalized
+// with WithKeyNormalizer, and then with every inferred extension appended.
+func (sm staticMap) candidates(name string) []string {
+	if sm.rewrite != nil {
+		name = sm.rewrite(name)
+	}
+	if sm.normalize != nil {
+		name = sm.normalize(name)
+	}
+	names := []string{name}
+	for _, ext := range sm.extensions {
+		names = append(names, name+ext)
+	}
+	return names
+}
+
+// find looks up the candidates for name in turn.
+func (sm staticMap) find(name string) (string, bool) {
+	for _, candidate := range sm.candidates(name) {
+		if value, ok := sm.lookup(candidate); ok {
+			return value, true
 		}
 	}
-	return defaultValue, false
+	return "", false
+}
+
+func (sm staticMap) lookup(name string) (string, bool) {
+	if sm.useMinified {
+		minifiedName := toMinifiedName(name)
+		if value, ok := sm.innerMap[minifiedName]; ok {
+			return value, true
+		}
+	}
+	value, ok := sm.innerMap[name]
+	if ok && sm.useMinified && sm.minifiedOnly && !isMinifiedName(name) {
+		return "", true
+	}
+	return value, ok
 }
 
 func toMinifiedName(name string) string {
@@ -200,24 +798,106 @@ func toMinifiedName(name string) string {
 	return strings.TrimSuffix(name, ext) + ".min" + ext
 }
 
-func createMapping(load Loader, path string, useMinified bool) (StaticMapper, error) {
-	var manifest interface{}
+func isMinifiedName(name string) bool {
+	return strings.HasSuffix(strings.TrimSuffix(name, filepath.Ext(name)), ".min")
+}
+
+func createMapping(
+	load Loader, path string, parser ManifestParser, useMinified bool, transforms ...manifestTransform,
+) (StaticMapper, error) {
 	if load != nil {
 		content, err := load(path)
 		if err != nil {
 			return nil, err
 		}
-		err = json.Unmarshal(content, &manifest)
+		innerMap, err := parser.Parse(content)
 		if err != nil {
+			if !errors.Is(err, ErrInvalidManifest) {
+				err = &invalidManifestError{err}
+			}
 			return nil, err
 		}
-		return &staticMap{manifest.(map[string]interface{}), useMinified}, nil
+		for _, transform := range transforms {
+			innerMap = transform(innerMap)
+		}
+		return &staticMap{innerMap: innerMap, useMinified: useMinified}, nil
 	}
-	return &staticMap{map[string]interface{}{}, useMinified}, nil
+	return &staticMap{innerMap: map[string]string{}, useMinified: useMinified}, nil
+}
+
+// createMapping creates the mapping from the manifest returned by load for path, applying the configured options.
+func (st *Static) createMapping(load Loader, path string) (StaticMapper, error) {
+	if st.decompress != nil && load != nil {
+		load = decompressingLoader(load, st.decompress)
+	}
+	if st.validators != nil && load != nil {
+		load = validatingLoader(load, st.validators)
+	}
+	if st.manifestFormat == ManifestFormatWebpackStats {
+		return loadWebpackStats(load, path)
+	}
+	parser := st.manifestParser
+	var linked map[string][]string
+	if st.manifestFormat == ManifestFormatVite {
+		linked = map[string][]string{}
+		parser = viteParser{linked}
+	}
+	mapping, err := createMapping(load, path, parser, st.useMinified, st.transforms...)
+	if err != nil {
+		return nil, err
+	}
+	sm := mapping.(*staticMap)
+	sm.linked = linked
+	return st.configureMapping(sm), nil
+}
+
+// mappingFromMap returns the mapping of the entries of m, to which the manifest transforms are applied.
+func (st *Static) mappingFromMap(m map[string]string) StaticMapper {
+	innerMap := make(map[string]string, len(m))
+	for key, value := range m {
+		innerMap[key] = value
+	}
+	for _, transform := range st.transforms {
+		innerMap = transform(innerMap)
+	}
+	return st.configureMapping(&staticMap{innerMap: innerMap, useMinified: st.useMinified})
+}
+
+// configureMapping applies the options changing lookups to sm.
+func (st *Static) configureMapping(sm *staticMap) *staticMap {
+	if st.keyNormalizer != nil {
+		sm.innerMap = transformManifestKeys(sm.innerMap, st.keyNormalizer)
+		sm.normalize = st.keyNormalizer
+	}
+	if st.pathRewrites != nil {
+		sm.rewrite = st.rewritePath
+	}
+	if st.stripExtension {
+		sm.extensions = st.inferredExts
+	}
+	sm.minifiedOnly = st.minifiedOnly
+	return sm
 }
 
 type optionSetter func(*Static)
 
+// WithOptionError can be used by packages providing options for NewStatic to make it return err, e.g. when
+// an option gets invalid arguments. A nil err is ignored.
+func WithOptionError(err error) optionSetter {
+	return func(st *Static) {
+		if err != nil {
+			st.setOptionErr(err)
+		}
+	}
+}
+
+// setOptionErr records an invalid option, which is then returned from NewStatic.
+func (st *Static) setOptionErr(err error) {
+	if st.optionErr == nil {
+		st.optionErr = err
+	}
+}
+
 // Loader returns file contents for a given path
 type Loader func(string) ([]byte, error)
 
@@ -226,17 +906,235 @@ func WithManifestLoader(load Loader) optionSetter {
 	return func(st *Static) { st.manifestLoader = load }
 }
 
+// WithManifestFromReader can be used in NewStatic to read the manifest from r (e.g. os.Stdin)
+// instead of loading it from manifestPath. The reader is consumed once and not retained.
+func WithManifestFromReader(r io.Reader) optionSetter {
+	return func(st *Static) {
+		content, err := ioutil.ReadAll(r)
+		st.manifestLoader = func(name string) ([]byte, error) {
+			if name != st.manifestPath {
+				return nil, fs.ErrNotExist
+			}
+			return content, err
+		}
+	}
+}
+
+// WithFS can be used in NewStatic to provide the file system from which asset files are read. With
+// WithRequestOptions the SRI hashes cached for the file system of the original instance aren't used.
+func WithFS(fsys fs.FS) optionSetter {
+	return func(st *Static) {
+		st.fsys = fsys
+		st.sriCache = &sync.Map{}
+	}
+}
+
+// WithDistDir can be used in NewStatic when the manifest and the built assets live in a single
+// directory: the manifest is loaded from dir/manifest.json and asset files are read from dir.
+func WithDistDir(dir string) optionSetter {
+	return func(st *Static) {
+		st.manifestPath = filepath.Join(dir, "manifest.json")
+		st.manifestLoader = ioutil.ReadFile
+		WithFS(os.DirFS(dir))(st)
+	}
+}
+
 // WithMappingBuilder can be used to provide MappingBuilder implementation in NewStatic
 func WithMappingBuilder(builder MappingBuilder) optionSetter {
 	return func(st *Static) { st.mappingBuilder = builder }
 }
 
+// WithInitialManifest can be used in NewStatic to start with mapper instead of building the mapping, e.g.
+// when the manifest is loaded in the background and replaced with SwapManifest or ReloadManifest then.
+func WithInitialManifest(mapper StaticMapper) optionSetter {
+	return func(st *Static) { st.mapping = mapper }
+}
+
 // WithUseMinified can be used in NewStatic to specify whether resoruce mapping should be used.
 // false can be useful in debug mode.
 func WithUseMinified(minified bool) optionSetter {
 	return func(st *Static) { st.useMinified = minified }
 }
 
+// WithMinifiedFallback can be used in NewStatic together with WithUseMinified(true) to control what happens
+// when an asset is in the manifest, but its minified variant isn't. By default (true) the non-minified version
+// is used; with false the asset is resolved to an empty path instead, and with WithStrictMode(true) the tag
+// functions return ErrAssetNotFound.
+func WithMinifiedFallback(fallbackToOriginal bool) optionSetter {
+	return func(st *Static) { st.minifiedOnly = !fallbackToOriginal }
+}
+
+// WithStrictMode can be used in NewStatic to make tag functions, e.g. ScriptTag and LinkTag, return
+// ErrAssetNotFound for assets missing in the manifest instead of using their paths as is. Useful in production
+// to catch deployments with a stale manifest. Disabled by default.
+func WithStrictMode(strict bool) optionSetter {
+	return func(st *Static) { st.strictMode = strict }
+}
+
+// WithFallbackToOriginal can be used in NewStatic to control how assets missing in the manifest are resolved:
+// to their original paths (true, the default) or to an empty path (false).
+func WithFallbackToOriginal(fallback bool) optionSetter {
+	if fallback {
+		return WithFallbackFunc(nil)
+	}
+	return WithFallbackFunc(func(string) string { return "" })
+}
+
+// WithFallbackFunc can be used in NewStatic to resolve assets missing in the manifest with fn, e.g. to add
+// a version query string. fn receives the asset path; nil restores the default of using it as is.
+func WithFallbackFunc(fn func(path string) string) optionSetter {
+	return func(st *Static) { st.fallback = fn }
+}
+
+// WithAssetCDNRewriter can be used in NewStatic to provide a function transforming asset URLs,
+// e.g. to follow a legacy CDN scheme. The function receives the prefixed and resolved path.
+func WithAssetCDNRewriter(fn func(resolvedPath string) string) optionSetter {
+	return func(st *Static) { st.cdnRewriter = fn }
+}
+
+// WithURLTransformer can be used in NewStatic to build asset URLs with fn, e.g. to add a deploy query string or
+// sign them. fn receives the resolved path and returns the URL used in tags. It takes full responsibility for
+// the URL: the URL prefixes, WithURLEncode and WithAssetCDNRewriter aren't applied.
+func WithURLTransformer(fn func(resolvedPath string) string) optionSetter {
+	return func(st *Static) { st.urlTransformer = fn }
+}
+
+// WithURLTransformerFunc is an alias of WithURLTransformer, named like WithFallbackFunc.
+func WithURLTransformerFunc(fn func(resolvedPath string) string) optionSetter {
+	return WithURLTransformer(fn)
+}
+
+// WithStaticPrefix can be used in NewStatic instead of the urlPrefix argument, which it overrides. Unlike
+// urlPrefix, prefix is validated: it must be an absolute URL, a path starting with "/", or empty.
+// Otherwise NewStatic returns an error.
+func WithStaticPrefix(prefix string) optionSetter {
+	return func(st *Static) {
+		if err := validatePrefix(prefix); err != nil {
+			st.setOptionErr(err)
+			return
+		}
+		st.urlPrefix = prefix
+	}
+}
+
+func validatePrefix(prefix string) error {
+	if prefix == "" {
+		return nil
+	}
+	parsed, err := url.Parse(prefix)
+	if err != nil {
+		return err
+	}
+	if parsed.IsAbs() && parsed.Host != "" || !parsed.IsAbs() && strings.HasPrefix(prefix, "/") {
+		return nil
+	}
+	return fmt.Errorf("invalid static prefix %q", prefix)
+}
+
+// WithScriptURLPrefix can be used in NewStatic to use prefix instead of the URL prefix in the tags emitted
+// by ScriptTag, e.g. to serve scripts from a different CDN origin than stylesheets.
+func WithScriptURLPrefix(prefix string) optionSetter {
+	return func(st *Static) { st.scriptPrefix = prefix }
+}
+
+// WithLinkURLPrefix can be used in NewStatic to use prefix instead of the URL prefix in the tags emitted
+// by LinkTag. See WithScriptURLPrefix.
+func WithLinkURLPrefix(prefix string) optionSetter {
+	return func(st *Static) { st.linkPrefix = prefix }
+}
+
+// typePrefix returns prefix set for a tag type with a trailing "/", or the URL prefix if it's not set.
+func (st *Static) typePrefix(prefix string) string {
+	if prefix == "" {
+		return st.urlPrefix
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return prefix
+}
+
+// WithURLEncode can be used in NewStatic to percent-encode resolved asset paths in URLs. The URL prefix is
+// trusted and left as is. Disabled by default.
+func WithURLEncode(enabled bool) optionSetter {
+	return func(st *Static) { st.urlEncode = enabled }
+}
+
+// WithCacheHitCounter can be used in NewStatic to instrument manifest lookups: counter is called for every
+// looked up asset with its path and whether it was found in the manifest (resolved to a different path).
+// counter is called synchronously, so it mustn't block, and it must be safe for concurrent use.
+func WithCacheHitCounter(counter func(key string, hit bool)) optionSetter {
+	return func(st *Static) { st.hitCounter = counter }
+}
+
+// WithDisableEscaping can be used in NewStatic to stop escaping attribute values of the emitted tags, for
+// callers passing values that are already escaped. Attribute names are still escaped. WARNING: with escaping
+// disabled the caller must guarantee that all the values, including the resolved URLs, are safe to insert
+// into HTML. Disabled by default.
+func WithDisableEscaping(disabled bool) optionSetter {
+	return func(st *Static) { st.disableEscaping = disabled }
+}
+
+// WithHTMLCommentAnnotation can be used in NewStatic to wrap tags emitted by ScriptTag and LinkTag
+// in HTML comments identifying the logical asset. Useful for debugging, disabled by default.
+func WithHTMLCommentAnnotation(enabled bool) optionSetter {
+	return func(st *Static) { st.annotateTags = enabled }
+}
+
+// WithDebugHTMLComments can be used in NewStatic to prepend tags emitted by the template functions with
+// HTML comments showing the logical asset, the value it's resolved to in the manifest, and whether the
+// minified variant is used, e.g. "<!-- asset: js/app.js → dist/app-1234.min.js (minified) -->". Useful
+// during development, disabled by default.
+func WithDebugHTMLComments(enabled bool) optionSetter {
+	return func(st *Static) { st.debugComments = enabled }
+}
+
+// WithDefaultScriptAttrs can be used in NewStatic to provide attributes, e.g. "defer", "defer", added to all
+// the tags emitted by ScriptTag. Attributes passed to ScriptTag take precedence. attrs that don't form pairs
+// make NewStatic return an error.
+func WithDefaultScriptAttrs(attrs ...string) optionSetter {
+	return func(st *Static) {
+		attrMap, err := attrSliceToMap(attrs)
+		if err != nil {
+			st.setOptionErr(err)
+			return
+		}
+		st.scriptAttrs = attrMap
+	}
+}
+
+// WithDefaultLinkAttrs can be used in NewStatic to provide attributes added to all the tags emitted by
+// LinkTag. See WithDefaultScriptAttrs.
+func WithDefaultLinkAttrs(attrs ...string) optionSetter {
+	return func(st *Static) {
+		attrMap, err := attrSliceToMap(attrs)
+		if err != nil {
+			st.setOptionErr(err)
+			return
+		}
+		st.linkAttrs = attrMap
+	}
+}
+
+// WithAssetAnnotation can be used in NewStatic to add attributes computed for every asset to the tags
+// emitted by ScriptTag and LinkTag, e.g. data-revision. fn receives the asset path and its URL; attributes
+// passed to the tag functions take precedence over the returned ones.
+func WithAssetAnnotation(fn func(key, resolvedURL string) map[string]string) optionSetter {
+	return func(st *Static) { st.annotator = fn }
+}
+
+// WithBuildTimestamp can be used in NewStatic to add the data-build attribute with the build time t, in
+// RFC 3339 format, to all the emitted tags. The zero time, the default, disables the attribute.
+func WithBuildTimestamp(t time.Time) optionSetter {
+	return func(st *Static) { st.buildTime = t }
+}
+
+func (st *Static) addBuildTimestamp(attrMap map[string]string) {
+	if !st.buildTime.IsZero() {
+		attrMap["data-build"] = st.buildTime.Format(time.RFC3339)
+	}
+}
+
 func attrSliceToMap(attrsSlice []string) (map[string]string, error) {
 	length := len(attrsSlice)
 	if length%2 != 0 {
@@ -249,11 +1147,15 @@ func attrSliceToMap(attrsSlice []string) (map[string]string, error) {
 	return attrMap, nil
 }
 
-func mapToAttrs(attrMap map[string]string) string {
+// mapToAttrs formats attributes sorted by key. Keys are always escaped; values only if escapeValues is true.
+func mapToAttrs(attrMap map[string]string, escapeValues bool) string {
 	attrSlice := make([]string, 0, len(attrMap))
 	for key, value := range attrMap {
+		if escapeValues {
+			value = html.EscapeString(value)
+		}
 		attr := fmt.Sprintf(
-			`%s="%s"`, html.EscapeString(key), html.EscapeString(value),
+			`%s="%s"`, html.EscapeString(key), value,
 		)
 		attrSlice = append(attrSlice, attr)
 	}
@@ -266,3 +1168,5 @@ func updateMap(updated map[string]string, updating map[string]string) {
 		updated[key] = value
 	}
 }
+
+var errManifestNotObject error = &invalidManifestError{errors.New("manifest is not a JSON object")}
@@ -1,9 +1,21 @@
 package asset
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"github.com/stretchr/testify/require"
+	"html/template"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"testing/iotest"
+	"time"
 )
 
 func TestMapToAttrs(t *testing.T) {
@@ -12,7 +24,8 @@ func TestMapToAttrs(t *testing.T) {
 		`"escape`: "me<",
 		"other":   "attribute ",
 	}
-	require.Equal(t, `&#34;escape="me&lt;" name="value" other="attribute "`, mapToAttrs(params))
+	require.Equal(t, `&#34;escape="me&lt;" name="value" other="attribute "`, mapToAttrs(params, true))
+	require.Equal(t, `&#34;escape="me<" name="value" other="attribute "`, mapToAttrs(params, false))
 }
 
 func TestAttrsSliceToMap(t *testing.T) {
@@ -40,7 +53,7 @@ func TestScriptTag(t *testing.T) {
 	tag, err := static.ScriptTag("js/other.js")
 	require.Nil(t, err)
 	require.Equal(t,
-		`<script src="/static/dist/other-1234.min.js" type="text/javascript"></script>`, tag,
+		template.HTML(`<script src="/static/dist/other-1234.min.js" type="text/javascript"></script>`), tag,
 	)
 }
 
@@ -51,7 +64,7 @@ func TestScriptTagParams(t *testing.T) {
 	tag, err := static.ScriptTag("js/other.js", "data-main", "some value", "defer", "defer")
 	require.Nil(t, err)
 	require.Equal(t,
-		`<script data-main="some value" defer="defer" src="/static/js/other.js" type="text/javascript"></script>`, tag,
+		template.HTML(`<script data-main="some value" defer="defer" src="/static/js/other.js" type="text/javascript"></script>`), tag,
 	)
 }
 
@@ -74,7 +87,7 @@ func TestLinkTag(t *testing.T) {
 	tag, err := static.LinkTag("css/other.css")
 	require.Nil(t, err)
 	require.Equal(t,
-		`<link href="/static/dist/other-1234.min.css" rel="stylesheet" type="text/css"/>`, tag,
+		template.HTML(`<link href="/static/dist/other-1234.min.css" rel="stylesheet" type="text/css"/>`), tag,
 	)
 }
 
@@ -85,7 +98,7 @@ func TestLinkTagParams(t *testing.T) {
 	tag, err := static.LinkTag("css/other.css", "media", "some value", "title", "whatever")
 	require.Nil(t, err)
 	require.Equal(t,
-		`<link href="/static/css/other.css" media="some value" rel="stylesheet" title="whatever" type="text/css"/>`, tag,
+		template.HTML(`<link href="/static/css/other.css" media="some value" rel="stylesheet" title="whatever" type="text/css"/>`), tag,
 	)
 }
 
@@ -98,7 +111,7 @@ func TestLinkTagOddParams(t *testing.T) {
 }
 
 func TestCreateMappingNoLoader(t *testing.T) {
-	mapping, err := createMapping(nil, "filename", false)
+	mapping, err := createMapping(nil, "filename", JSONManifestParser{}, false)
 	require.Nil(t, err)
 	require.Equal(t, "name", mapping.Get("name"))
 }
@@ -107,7 +120,7 @@ func TestCreateMappingFileError(t *testing.T) {
 	loader := func(name string) ([]byte, error) {
 		return nil, errors.New("I/O Error")
 	}
-	_, err := createMapping(loader, "filename", false)
+	_, err := createMapping(loader, "filename", JSONManifestParser{}, false)
 	require.NotNil(t, err)
 }
 
@@ -115,7 +128,7 @@ func TestCreateMappingInvalidJSON(t *testing.T) {
 	loader := func(name string) ([]byte, error) {
 		return []byte("garbage"), nil
 	}
-	_, err := createMapping(loader, "filename", false)
+	_, err := createMapping(loader, "filename", JSONManifestParser{}, false)
 	require.NotNil(t, err)
 }
 
@@ -125,7 +138,7 @@ func TestCreateMappingNotMinified(t *testing.T) {
 			`{"js/name.js":"dist/name-1234.js", "js/other.min.js":"dist/name-1234.min.js", "js/name.min.js":"dist/name-1234.min.js"}`,
 		), nil
 	}
-	mapping, err := createMapping(loader, "filename", false)
+	mapping, err := createMapping(loader, "filename", JSONManifestParser{}, false)
 	require.Nil(t, err)
 	require.Equal(t, "dist/name-1234.js", mapping.Get("js/name.js"))
 	require.Equal(t, "js/other.js", mapping.Get("js/other.js"))
@@ -137,8 +150,539 @@ func TestCreateMappingMinified(t *testing.T) {
 			`{"js/name.js":"dist/name-1234.js", "js/other.min.js":"dist/other-1234.min.js", "js/other.js": "dist/other-1234.js"}`,
 		), nil
 	}
-	mapping, err := createMapping(loader, "filename", true)
+	mapping, err := createMapping(loader, "filename", JSONManifestParser{}, true)
 	require.Nil(t, err)
 	require.Equal(t, "dist/name-1234.js", mapping.Get("js/name.js"))
 	require.Equal(t, "dist/other-1234.min.js", mapping.Get("js/other.js"))
 }
+
+func TestURLFor(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	static, err := NewStatic("/static", "data/manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+	require.Equal(t, "/static/js/other.js", static.URLFor("js/other.js"))
+}
+
+func TestAssetCDNRewriter(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	rewriter := func(resolvedPath string) string {
+		return strings.Replace(resolvedPath, "/static/dist/", "/cdn/js/1234/", 1)
+	}
+	static, err := NewStatic("/static", "data/manifest.json", WithManifestLoader(loader), WithAssetCDNRewriter(rewriter))
+	require.Nil(t, err)
+	require.Equal(t, "/cdn/js/1234/app-1234.js", static.URLFor("js/app.js"))
+	tag, err := static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t,
+		template.HTML(`<script src="/cdn/js/1234/app-1234.js" type="text/javascript"></script>`), tag,
+	)
+}
+
+func TestURLTransformer(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"dist/app-1234.js","css/style.css":"dist/style-1234.css"}`), nil
+	}
+	deploy := func(resolvedPath string) string { return "/assets/" + resolvedPath + "?deploy=abc123" }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithURLTransformer(deploy))
+	require.Nil(t, err)
+	require.Equal(t, "/assets/dist/app-1234.js?deploy=abc123", static.URLFor("js/app.js"))
+	tag, err := static.LinkTag("css/style.css")
+	require.Nil(t, err)
+	require.Equal(t,
+		template.HTML(`<link href="/assets/dist/style-1234.css?deploy=abc123" rel="stylesheet" type="text/css"/>`), tag,
+	)
+
+	sign := func(resolvedPath string) string {
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(resolvedPath))
+		return "https://cdn.example.com/" + resolvedPath + "?sig=" + hex.EncodeToString(mac.Sum(nil))[:16]
+	}
+	static, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithURLTransformerFunc(sign))
+	require.Nil(t, err)
+	tag, err = static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<script src="`+sign("dist/app-1234.js")+`" type="text/javascript"></script>`), tag)
+	require.True(t, strings.HasPrefix(static.URLFor("js/other.js"), "https://cdn.example.com/js/other.js?sig="))
+}
+
+func TestDebugHTMLComments(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"dist/app-1234.js", "js/app.min.js":"dist/app-1234.min.js",
+			"css/style.css":"dist/style-1234.css"}`), nil
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader),
+		WithUseMinified(true), WithDebugHTMLComments(true))
+	require.Nil(t, err)
+	tag, err := static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<!-- asset: js/app.js → dist/app-1234.min.js (minified) -->`+
+		`<script src="/static/dist/app-1234.min.js" type="text/javascript"></script>`), tag)
+	tag, err = static.LinkTag("css/style.css")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<!-- asset: css/style.css → dist/style-1234.css -->`+
+		`<link href="/static/dist/style-1234.css" rel="stylesheet" type="text/css"/>`), tag)
+	tag, err = static.FaviconTag("<favicon>.ico")
+	require.Nil(t, err)
+	require.True(t, strings.HasPrefix(string(tag), `<!-- asset: &lt;favicon&gt;.ico → &lt;favicon&gt;.ico --><link `))
+
+	static, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithDebugHTMLComments(false))
+	require.Nil(t, err)
+	tag, err = static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<script src="/static/dist/app-1234.js" type="text/javascript"></script>`), tag)
+}
+
+func TestHTMLCommentAnnotation(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	static, err := NewStatic("/static", "data/manifest.json", WithManifestLoader(loader), WithHTMLCommentAnnotation(true))
+	require.Nil(t, err)
+	tag, err := static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t,
+		template.HTML(`<!-- asset: js/app.js --><script src="/static/dist/app-1234.js" type="text/javascript"></script><!-- /asset -->`), tag,
+	)
+	tag, err = static.LinkTag("css/style.css")
+	require.Nil(t, err)
+	require.Equal(t,
+		template.HTML(`<!-- asset: css/style.css --><link href="/static/css/style.css" rel="stylesheet" type="text/css"/><!-- /asset -->`), tag,
+	)
+}
+
+func TestDefaultAttrs(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil),
+		WithDefaultScriptAttrs("defer", "defer", "data-app", "main"), WithDefaultLinkAttrs("media", "screen"))
+	require.Nil(t, err)
+	tag, err := static.ScriptTag("js/app.js", "data-app", "admin")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<script data-app="admin" defer="defer" src="/static/js/app.js" type="text/javascript"></script>`,
+	), tag)
+	tag, err = static.LinkTag("css/style.css")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<link href="/static/css/style.css" media="screen" rel="stylesheet" type="text/css"/>`), tag)
+	tag, err = static.LinkTag("css/print.css", "media", "print")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<link href="/static/css/print.css" media="print" rel="stylesheet" type="text/css"/>`), tag)
+
+	_, err = NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithDefaultScriptAttrs("defer"))
+	require.NotNil(t, err)
+	_, err = NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithDefaultLinkAttrs("media"))
+	require.NotNil(t, err)
+}
+
+func TestAssetAnnotation(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	annotator := func(key, resolvedURL string) map[string]string {
+		return map[string]string{"data-key": key, "data-url": resolvedURL, "type": "module"}
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithAssetAnnotation(annotator))
+	require.Nil(t, err)
+	tag, err := static.ScriptTag("js/app.js", "data-key", "app")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<script data-key="app" data-url="/static/dist/app-1234.js" src="/static/dist/app-1234.js" type="module"></script>`,
+	), tag)
+	tag, err = static.LinkTag("css/style.css", "type", "text/css")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<link data-key="css/style.css" data-url="/static/css/style.css" href="/static/css/style.css" rel="stylesheet" type="text/css"/>`,
+	), tag)
+}
+
+func TestBuildTimestamp(t *testing.T) {
+	built := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithBuildTimestamp(built),
+		WithResourceHints([]ResourceHint{{Rel: "preconnect", Path: "https://cdn.example.com"}}))
+	require.Nil(t, err)
+	tag, err := static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<script data-build="2024-05-01T12:30:00Z" src="/static/js/app.js" type="text/javascript"></script>`,
+	), tag)
+	tag, err = static.LinkTag("css/style.css")
+	require.Nil(t, err)
+	require.Contains(t, tag, `data-build="2024-05-01T12:30:00Z"`)
+	tag, err = static.PreloadTag("js/app.js", "script")
+	require.Nil(t, err)
+	require.Contains(t, tag, `data-build="2024-05-01T12:30:00Z"`)
+	tag, err = static.ResourceHints()
+	require.Nil(t, err)
+	require.Contains(t, tag, `data-build="2024-05-01T12:30:00Z"`)
+
+	static, err = NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithBuildTimestamp(time.Time{}))
+	require.Nil(t, err)
+	tag, err = static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.NotContains(t, tag, "data-build")
+}
+
+func TestManifestFromReader(t *testing.T) {
+	reader := strings.NewReader(`{"js/app.js":"dist/app-1234.js"}`)
+	static, err := NewStatic("/static", "stdin", WithManifestFromReader(reader))
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+}
+
+func TestManifestFromReaderError(t *testing.T) {
+	reader := iotest.ErrReader(errors.New("I/O Error"))
+	_, err := NewStatic("/static", "stdin", WithManifestFromReader(reader))
+	require.NotNil(t, err)
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestNewStaticFromReader(t *testing.T) {
+	static, err := NewStaticFromReader("/static", strings.NewReader(`{"js/app.min.js":"dist/app-1234.min.js"}`),
+		WithUseMinified(true))
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app-1234.min.js", static.URLFor("js/app.js"))
+
+	reader := &closeRecorder{Reader: strings.NewReader(`{}`)}
+	_, err = NewStaticFromReader("/static", reader)
+	require.Nil(t, err)
+	require.True(t, reader.closed)
+
+	_, err = NewStaticFromReader("/static", strings.NewReader(`[]`))
+	require.True(t, errors.Is(err, ErrInvalidManifest))
+	_, err = NewStaticFromReader("/static", iotest.ErrReader(errors.New("I/O Error")))
+	require.NotNil(t, err)
+}
+
+func TestNewStaticFromMap(t *testing.T) {
+	static, err := NewStaticFromMap("/static", map[string]string{
+		"js/app.js":     "dist/app-1234.js",
+		"js/app.min.js": "dist/app-1234.min.js",
+	}, WithUseMinified(true))
+	require.Nil(t, err)
+	tag, err := static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<script src="/static/dist/app-1234.min.js" type="text/javascript"></script>`), tag)
+	require.Equal(t, "/static/js/other.js", static.URLFor("js/other.js"))
+}
+
+func TestNewStaticFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"dist/manifest.json": {Data: []byte(`{"js/app.js":"app-1234.js"}`)},
+		"app-1234.js":        {Data: []byte("alert(1)")},
+	}
+	static, err := NewStaticFromFS("/static", fsys, "dist/manifest.json", WithUseSri(true))
+	require.Nil(t, err)
+	tag, err := static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<script crossorigin="anonymous" integrity="sha256-bhHHL3z2vDgxUt0W3dWQOrprscmda2Y5pLsLg4GF+pI=" `+
+			`src="/static/app-1234.js" type="text/javascript"></script>`,
+	), tag)
+
+	_, err = NewStaticFromFS("/static", fsys, "missing.json")
+	require.True(t, errors.Is(err, fs.ErrNotExist))
+}
+
+func TestDistDir(t *testing.T) {
+	dir := t.TempDir()
+	manifest := []byte(`{"js/app.js":"js/app-1234.js"}`)
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "manifest.json"), manifest, 0644))
+	static, err := NewStatic("/static", "", WithDistDir(dir))
+	require.Nil(t, err)
+	require.Equal(t, filepath.Join(dir, "manifest.json"), static.manifestPath)
+	require.NotNil(t, static.fsys)
+	require.Equal(t, "/static/js/app-1234.js", static.URLFor("js/app.js"))
+}
+
+func TestURLEncode(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"dist/my app[1].js", "js/other.js":"dist/other.js?v=a b&x"}`), nil
+	}
+	static, err := NewStatic("/static path", "manifest.json", WithManifestLoader(loader), WithURLEncode(true))
+	require.Nil(t, err)
+	require.Equal(t, "/static path/dist/my%20app%5B1%5D.js", static.URLFor("js/app.js"))
+	require.Equal(t, "/static path/dist/other.js?v=a+b&x", static.URLFor("js/other.js"))
+	static, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/my app[1].js", static.URLFor("js/app.js"))
+}
+
+func TestToGinH(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil))
+	require.Nil(t, err)
+	h := static.ToGinH()
+	require.Len(t, h, len(static.FuncMap()))
+	for name := range static.FuncMap() {
+		require.Contains(t, h, name)
+	}
+}
+
+func TestCreateMappingNotObject(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`["js/app.js"]`), nil }
+	_, err := createMapping(loader, "filename", JSONManifestParser{}, false)
+	require.Equal(t, errManifestNotObject, err)
+}
+
+func TestTemplate(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	tmpl, err := static.Template().Parse(`{{ scripttag "js/app.js" }}`)
+	require.Nil(t, err)
+	var out strings.Builder
+	require.Nil(t, tmpl.Execute(&out, nil))
+	require.Equal(t, `<script src="/static/dist/app-1234.js" type="text/javascript"></script>`, out.String())
+	require.False(t, static.Template() == static.Template())
+}
+
+func TestStrictMode(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	for _, strict := range []bool{false, true} {
+		static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithStrictMode(strict))
+		require.Nil(t, err)
+		tmpl, err := static.Template().Parse(`{{ scripttag "js/app.js" }}{{ linktag "css/missing.css" }}`)
+		require.Nil(t, err)
+		err = tmpl.Execute(ioutil.Discard, nil)
+		if !strict {
+			require.Nil(t, err)
+			continue
+		}
+		require.True(t, errors.Is(err, ErrAssetNotFound))
+		require.Contains(t, err.Error(), "css/missing.css")
+		_, err = static.ScriptTag("js/app.js")
+		require.Nil(t, err)
+	}
+}
+
+func TestStrictModeIdentityEntry(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"favicon.ico":"favicon.ico"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithStrictMode(true))
+	require.Nil(t, err)
+	require.True(t, static.Has("favicon.ico"))
+	tag, err := static.FaviconTag("favicon.ico")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<link href="/static/favicon.ico" rel="icon" type="image/x-icon"/>`), tag)
+	require.Equal(t, "/static/favicon.ico", static.MustGetURL("favicon.ico"))
+	_, err = static.FaviconTag("other.ico")
+	require.True(t, errors.Is(err, ErrAssetNotFound))
+}
+
+func TestGetURL(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app-1234.js", static.GetURL("js/app.js"))
+	require.Equal(t, "/static/js/other.js", static.GetURL("js/other.js"))
+	require.Equal(t, "/static/js/other.js", static.MustGetURL("js/other.js"))
+
+	static, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithStrictMode(true))
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app-1234.js", static.MustGetURL("js/app.js"))
+	require.Equal(t, "/static/js/other.js", static.GetURL("js/other.js"))
+	require.Panics(t, func() { static.MustGetURL("js/other.js") })
+}
+
+func TestHas(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.min.js":"dist/app-1234.min.js","css/style.css":"dist/style-1234.css"}`), nil
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithUseMinified(true))
+	require.Nil(t, err)
+	require.True(t, static.Has("js/app.js"))
+	require.True(t, static.Has("js/app.min.js"))
+	require.True(t, static.Has("css/style.css"))
+	require.False(t, static.Has("js/other.js"))
+
+	static, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	require.False(t, static.Has("js/app.js"))
+	require.True(t, static.Has("js/app.min.js"))
+}
+
+func TestResolve(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.min.js":"dist/app-1234.min.js","css/style.css":"dist/style-1234.css"}`), nil
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithUseMinified(true))
+	require.Nil(t, err)
+	resolved, minified := static.Resolve("js/app.js")
+	require.Equal(t, "dist/app-1234.min.js", resolved)
+	require.True(t, minified)
+	resolved, minified = static.Resolve("js/app.min.js")
+	require.Equal(t, "dist/app-1234.min.js", resolved)
+	require.False(t, minified)
+	resolved, minified = static.Resolve("css/style.css")
+	require.Equal(t, "dist/style-1234.css", resolved)
+	require.False(t, minified)
+	resolved, minified = static.Resolve("js/other.js")
+	require.Equal(t, "js/other.js", resolved)
+	require.False(t, minified)
+
+	static, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	_, minified = static.Resolve("js/app.min.js")
+	require.False(t, minified)
+}
+
+func TestFallbackToOriginal(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithFallbackToOriginal(false))
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+	require.Equal(t, "/static/", static.URLFor("js/other.js"))
+
+	static, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader),
+		WithFallbackToOriginal(false), WithStrictMode(true))
+	require.Nil(t, err)
+	_, err = static.ScriptTag("js/other.js")
+	require.True(t, errors.Is(err, ErrAssetNotFound))
+}
+
+func TestFallbackFunc(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"dist/app-1234.js","favicon.ico":"favicon.ico"}`), nil
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader),
+		WithFallbackFunc(func(path string) string { return path + "?v=1700000000" }))
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+	require.Equal(t, "/static/favicon.ico", static.URLFor("favicon.ico"))
+	tag, err := static.ScriptTag("js/other.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<script src="/static/js/other.js?v=1700000000" type="text/javascript"></script>`), tag)
+}
+
+func TestCacheHitCounter(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	hits := map[string]bool{}
+	counter := func(key string, hit bool) { hits[key] = hit }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithCacheHitCounter(counter))
+	require.Nil(t, err)
+	_, err = static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	static.URLFor("js/other.js")
+	require.Equal(t, map[string]bool{"js/app.js": true, "js/other.js": false}, hits)
+}
+
+func TestMinifiedFallback(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"dist/app-1234.js", "js/other.js":"dist/other-1234.js", "js/other.min.js":"dist/other-1234.min.js"}`), nil
+	}
+	static, err := NewStatic(
+		"/static", "manifest.json", WithManifestLoader(loader), WithUseMinified(true), WithMinifiedFallback(false),
+	)
+	require.Nil(t, err)
+	require.Equal(t, "/static/", static.URLFor("js/app.js"))
+	require.Equal(t, "/static/dist/other-1234.min.js", static.URLFor("js/other.js"))
+	require.Equal(t, "/static/dist/other-1234.min.js", static.URLFor("js/other.min.js"))
+	require.Equal(t, "/static/js/unknown.js", static.URLFor("js/unknown.js"))
+
+	static, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader),
+		WithUseMinified(true), WithMinifiedFallback(false), WithStrictMode(true))
+	require.Nil(t, err)
+	_, err = static.ScriptTag("js/app.js")
+	require.True(t, errors.Is(err, ErrAssetNotFound))
+	tag, err := static.ScriptTag("js/other.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<script src="/static/dist/other-1234.min.js" type="text/javascript"></script>`), tag)
+
+	static, err = NewStatic(
+		"/static", "manifest.json", WithManifestLoader(loader), WithUseMinified(true), WithMinifiedFallback(true),
+	)
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+}
+
+func TestStaticPrefix(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	for prefix, expected := range map[string]string{
+		"/assets":                  "/assets/dist/app-1234.js",
+		"https://cdn.example.com/": "https://cdn.example.com/dist/app-1234.js",
+		"":                         "/dist/app-1234.js",
+	} {
+		static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithStaticPrefix(prefix))
+		require.Nil(t, err)
+		require.Equal(t, expected, static.URLFor("js/app.js"))
+	}
+	for _, prefix := range []string{"static", "https://", "mailto:someone@example.com", "/%zz"} {
+		_, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithStaticPrefix(prefix))
+		require.NotNil(t, err, prefix)
+	}
+}
+
+func TestAttachFuncs(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil))
+	require.Nil(t, err)
+	upper := func(s string) string { return strings.ToUpper(s) }
+	tmpl := template.New("")
+	static.AttachFuncs(template.FuncMap{"upper": upper}).Attach(tmpl)
+	template.Must(tmpl.Parse(`{{ upper "x" }} {{ static }}`))
+	var out strings.Builder
+	require.Nil(t, tmpl.Execute(&out, nil))
+	require.Equal(t, "X /static/", out.String())
+	require.Contains(t, static.FuncMap(), "scripttag")
+}
+
+func TestDisableEscaping(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithDisableEscaping(true))
+	require.Nil(t, err)
+	tag, err := static.ScriptTag("js/app.js", "data-query", "a=1&amp;b=2", "<key>", "value")
+	require.Nil(t, err)
+	require.Equal(t,
+		template.HTML(`<script &lt;key&gt;="value" data-query="a=1&amp;b=2" src="/static/js/app.js" type="text/javascript"></script>`), tag,
+	)
+}
+
+func TestRenderTemplate(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	out, err := static.RenderTemplate(`<div>{{ . }}</div>{{ scripttag "js/app.js" }}`, "<b>")
+	require.Nil(t, err)
+	require.Equal(t,
+		template.HTML(`<div>&lt;b&gt;</div><script src="/static/dist/app-1234.js" type="text/javascript"></script>`), out,
+	)
+	_, err = static.RenderTemplate(`{{ unknown }}`, nil)
+	require.NotNil(t, err)
+	_, err = static.RenderTemplate(`{{ scripttag "js/app.js" "odd" }}`, nil)
+	require.NotNil(t, err)
+}
+
+func TestAssetFingerprint(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"dist/app-da89a0c4.js", "css/style.css":"dist/style.min-16680603.css?v=1",
+			"js/short.js":"dist/short-1234.js", "js/word.js":"dist/some-deadbeefs.js", "js/dir.js":"dist-da89a0c4/app.js"}`), nil
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	fingerprint, ok := static.AssetFingerprint("js/app.js")
+	require.True(t, ok)
+	require.Equal(t, "da89a0c4", fingerprint)
+	fingerprint, ok = static.AssetFingerprint("css/style.css")
+	require.True(t, ok)
+	require.Equal(t, "16680603", fingerprint)
+	for _, path := range []string{"js/short.js", "js/word.js", "js/dir.js", "js/unknown.js"} {
+		fingerprint, ok = static.AssetFingerprint(path)
+		require.False(t, ok, path)
+		require.Equal(t, "", fingerprint)
+	}
+}
+
+func TestAllRegisteredFunctions(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil))
+	require.Nil(t, err)
+	static.AttachFuncs(template.FuncMap{"upper": strings.ToUpper})
+	names := AllRegisteredFunctions()
+	require.Equal(t, []string{
+		"appletouchicontag", "dnsprefetchtag", "emithead", "favicontag", "grouplinktags", "groupscripttags",
+		"imgtag", "inlinelinktag", "inlinescripttag", "linkmanifesttag", "linktag", "linktags", "mediatag",
+		"modulepreloadtag", "modulescripttag", "nomodulescripttag", "preconnecttag", "preloadtag", "scripttag",
+		"scripttags", "static",
+	}, names)
+	require.Equal(t, names, AllRegisteredFunctions())
+}
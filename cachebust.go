@@ -0,0 +1,60 @@
+package asset
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type cacheBustKey struct{}
+
+// WithHTTPHeaderCacheBust can be used in NewStatic to take a cache-bust version from the headerName request
+// header, e.g. X-Asset-Version set by a CDN. StaticMiddleware puts the version in the request context and
+// ScriptTagCtx and LinkTagCtx add it to the URLs as the v query parameter.
+func WithHTTPHeaderCacheBust(headerName string) optionSetter {
+	return func(st *Static) { st.cacheBustHeader = headerName }
+}
+
+// StaticMiddleware returns an http.Handler calling next with the cache-bust version read from the header
+// configured with WithHTTPHeaderCacheBust in the request context. Without the option it just calls next.
+func (st *Static) StaticMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if st.cacheBustHeader != "" {
+			if version := r.Header.Get(st.cacheBustHeader); version != "" {
+				r = r.WithContext(context.WithValue(r.Context(), cacheBustKey{}, version))
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ScriptTagCtx returns HTML script tag like ScriptTag, with the cache-bust version put in ctx by
+// StaticMiddleware, if any, added to the URL.
+func (st *Static) ScriptTagCtx(ctx context.Context, path string, attrs ...string) (template.HTML, error) {
+	return st.scriptTag(path, cacheBustVersion(ctx), "text/javascript", attrs)
+}
+
+// LinkTagCtx returns HTML link tag like LinkTag, with the cache-bust version put in ctx by
+// StaticMiddleware, if any, added to the URL.
+func (st *Static) LinkTagCtx(ctx context.Context, path string, attrs ...string) (template.HTML, error) {
+	return st.linkTag(path, cacheBustVersion(ctx), attrs)
+}
+
+func cacheBustVersion(ctx context.Context) string {
+	version, _ := ctx.Value(cacheBustKey{}).(string)
+	return version
+}
+
+// addVersion adds the v query parameter to assetURL, unless version is empty.
+func addVersion(assetURL string, version string) string {
+	if version == "" {
+		return assetURL
+	}
+	separator := "?"
+	if strings.Contains(assetURL, "?") {
+		separator = "&"
+	}
+	return assetURL + separator + "v=" + url.QueryEscape(version)
+}
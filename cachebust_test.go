@@ -0,0 +1,38 @@
+package asset
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPHeaderCacheBust(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"dist/app-1234.js","css/style.css":"dist/style.css?h=1"}`), nil
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithHTTPHeaderCacheBust("X-Asset-Version"))
+	require.Nil(t, err)
+	var script, link template.HTML
+	handler := static.StaticMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		script, err = static.ScriptTagCtx(r.Context(), "js/app.js")
+		require.Nil(t, err)
+		link, err = static.LinkTagCtx(r.Context(), "css/style.css")
+		require.Nil(t, err)
+	}))
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("X-Asset-Version", "v 2")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	require.Equal(t, template.HTML(`<script src="/static/dist/app-1234.js?v=v+2" type="text/javascript"></script>`), script)
+	require.Equal(t, template.HTML(`<link href="/static/dist/style.css?h=1&amp;v=v+2" rel="stylesheet" type="text/css"/>`), link)
+}
+
+func TestHTTPHeaderCacheBustMissing(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithHTTPHeaderCacheBust("X-Asset-Version"))
+	require.Nil(t, err)
+	tag, err := static.ScriptTagCtx(context.Background(), "js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<script src="/static/js/app.js" type="text/javascript"></script>`), tag)
+}
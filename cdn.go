@@ -0,0 +1,64 @@
+package asset
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultCDNPingPath    = "/health"
+	defaultCDNPingTimeout = 2 * time.Second
+)
+
+type cdnFailover struct {
+	primary  string
+	fallback string
+	status   string
+}
+
+// WithCDNFailover can be used in NewStatic to serve assets from the primary origin if it's reachable
+// at startup and from the fallback origin otherwise. Availability is checked with a HEAD request to
+// the primary origin followed by the ping path ("/health" unless changed with WithCDNPingPath).
+// The URL prefix passed to NewStatic is ignored.
+func WithCDNFailover(primary, fallback string) optionSetter {
+	return func(st *Static) {
+		st.cdn = &cdnFailover{primary: primary, fallback: fallback}
+	}
+}
+
+// WithCDNPingPath can be used together with WithCDNFailover to change the path requested to check
+// the primary origin availability. The options can be given in any order.
+func WithCDNPingPath(path string) optionSetter {
+	return func(st *Static) { st.cdnPingPath = path }
+}
+
+// WithCDNPingTimeout can be used together with WithCDNFailover to change how long to wait for
+// the primary origin to respond. The options can be given in any order.
+func WithCDNPingTimeout(timeout time.Duration) optionSetter {
+	return func(st *Static) { st.cdnPingTimeout = timeout }
+}
+
+// CDNStatus returns "primary" or "fallback" depending on which origin was selected by WithCDNFailover.
+// Returns an empty string when failover isn't configured.
+func (st *Static) CDNStatus() string {
+	if st.cdn == nil {
+		return ""
+	}
+	return st.cdn.status
+}
+
+// selectOrigin checks the primary origin by requesting pingPath and returns the URL prefix to use.
+func (cdn *cdnFailover) selectOrigin(pingPath string, timeout time.Duration) string {
+	client := &http.Client{Timeout: timeout}
+	response, err := client.Head(strings.TrimSuffix(cdn.primary, "/") + pingPath)
+	if err == nil {
+		response.Body.Close()
+		if response.StatusCode < http.StatusBadRequest {
+			cdn.status = "primary"
+			return cdn.primary
+		}
+	}
+	cdn.status = "fallback"
+	return cdn.fallback
+}
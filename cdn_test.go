@@ -0,0 +1,102 @@
+package asset
+
+import (
+	"github.com/stretchr/testify/require"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCDNFailoverPrimary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "HEAD", r.Method)
+		require.Equal(t, "/ping", r.URL.Path)
+	}))
+	defer server.Close()
+	static, err := NewStatic(
+		"/static", "", WithManifestLoader(nil),
+		WithCDNFailover(server.URL, "https://fallback.example.com"), WithCDNPingPath("/ping"),
+	)
+	require.Nil(t, err)
+	require.Equal(t, "primary", static.CDNStatus())
+	require.Equal(t, server.URL+"/js/app.js", static.URLFor("js/app.js"))
+}
+
+func TestCDNFailoverPingOptionsOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ping" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	static, err := NewStatic(
+		"/static", "", WithManifestLoader(nil), WithCDNPingPath("/ping"), WithCDNPingTimeout(time.Second),
+		WithCDNFailover(server.URL, "https://fallback.example.com"),
+	)
+	require.Nil(t, err)
+	require.Equal(t, "primary", static.CDNStatus())
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer slow.Close()
+	static, err = NewStatic(
+		"/static", "", WithManifestLoader(nil), WithCDNPingTimeout(10*time.Millisecond),
+		WithCDNFailover(slow.URL, "https://fallback.example.com"),
+	)
+	require.Nil(t, err)
+	require.Equal(t, "fallback", static.CDNStatus())
+}
+
+func TestCDNFailoverFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	static, err := NewStatic(
+		"/static", "", WithManifestLoader(nil), WithCDNFailover(server.URL, "https://fallback.example.com"),
+	)
+	require.Nil(t, err)
+	require.Equal(t, "fallback", static.CDNStatus())
+	require.Equal(t, "https://fallback.example.com/js/app.js", static.URLFor("js/app.js"))
+}
+
+func TestCDNFailoverUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+	static, err := NewStatic("/static", "", WithManifestLoader(nil), WithCDNFailover(url, "/static"))
+	require.Nil(t, err)
+	require.Equal(t, "fallback", static.CDNStatus())
+	require.Equal(t, "/static/js/app.js", static.URLFor("js/app.js"))
+}
+
+func TestCDNStatusNotConfigured(t *testing.T) {
+	static, err := NewStatic("/static", "", WithManifestLoader(nil))
+	require.Nil(t, err)
+	require.Equal(t, "", static.CDNStatus())
+}
+
+func TestPerTypeURLPrefix(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"dist/app-1234.js","css/style.css":"dist/style-1234.css"}`), nil
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader),
+		WithScriptURLPrefix("https://cdn-js.example.com"), WithLinkURLPrefix("https://cdn-css.example.com/"))
+	require.Nil(t, err)
+	tag, err := static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<script src="https://cdn-js.example.com/dist/app-1234.js" type="text/javascript"></script>`), tag)
+	tag, err = static.LinkTag("css/style.css")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<link href="https://cdn-css.example.com/dist/style-1234.css" rel="stylesheet" type="text/css"/>`), tag)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+
+	static, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithScriptURLPrefix("https://cdn-js.example.com"))
+	require.Nil(t, err)
+	tag, err = static.LinkTag("css/style.css")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<link href="/static/dist/style-1234.css" rel="stylesheet" type="text/css"/>`), tag)
+}
@@ -0,0 +1,34 @@
+// Package chi integrates go-asset-helper with the chi router.
+package chi
+
+import (
+	"context"
+	gochi "github.com/go-chi/chi/v5"
+	"github.com/rsniezynski/go-asset-helper"
+	"html/template"
+	"net/http"
+)
+
+type templatesKey struct{}
+
+// AttachChi parses the templates matching pattern with the functions of st attached and adds a middleware
+// to r making them available to the handlers through Templates. As chi has no renderer, the handlers
+// execute the templates themselves. Like every chi middleware, it must be attached before the routes.
+func AttachChi(r gochi.Router, st *asset.Static, pattern string) error {
+	templates, err := st.Template().ParseGlob(pattern)
+	if err != nil {
+		return err
+	}
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), templatesKey{}, templates)))
+		})
+	})
+	return nil
+}
+
+// Templates returns the templates attached with AttachChi to the router handling req, or nil.
+func Templates(req *http.Request) *template.Template {
+	templates, _ := req.Context().Value(templatesKey{}).(*template.Template)
+	return templates
+}
@@ -0,0 +1,30 @@
+package chi
+
+import (
+	gochi "github.com/go-chi/chi/v5"
+	"github.com/rsniezynski/go-asset-helper"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestAttachChi(t *testing.T) {
+	dir := t.TempDir()
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "page.html"), []byte(`{{ scripttag . }}`), 0644))
+	static, err := asset.NewStatic("/static", "manifest.json", asset.WithManifestLoader(nil))
+	require.Nil(t, err)
+	router := gochi.NewRouter()
+	require.Nil(t, AttachChi(router, static, filepath.Join(dir, "*.html")))
+	router.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		require.Nil(t, Templates(r).ExecuteTemplate(w, "page.html", "js/app.js"))
+	})
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+	require.Equal(t, `<script src="/static/js/app.js" type="text/javascript"></script>`, recorder.Body.String())
+
+	require.NotNil(t, AttachChi(gochi.NewRouter(), static, filepath.Join(dir, "*.missing")))
+	require.Nil(t, Templates(httptest.NewRequest("GET", "/", nil)))
+}
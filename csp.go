@@ -0,0 +1,142 @@
+package asset
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"strings"
+	"sync"
+	"time"
+)
+
+type nonceSet struct {
+	mu     sync.Mutex
+	nonces []string
+	seen   map[string]bool
+}
+
+func newNonceSet() *nonceSet {
+	return &nonceSet{seen: map[string]bool{}}
+}
+
+// WithNonce can be used in NewStatic or WithRequestOptions to add the nonce attribute with nonce to the tags
+// emitted by ScriptTag and LinkTag, for Content Security Policy. Attributes passed to the tag functions
+// take precedence.
+func WithNonce(nonce string) optionSetter {
+	return WithNonceFunc(func() string { return nonce })
+}
+
+// WithNonceFunc can be used in NewStatic or WithRequestOptions like WithNonce, with fn called for every
+// emitted tag to get the nonce.
+func WithNonceFunc(fn func() string) optionSetter {
+	return func(st *Static) { st.nonceFunc = fn }
+}
+
+var errSharedMappingOption = errors.New("option changing the shared manifest mapping can't be applied per request")
+
+// WithRequestOptions returns a copy of st, created with Clone, with options applied, e.g. WithNonce with
+// the nonce of a request. st isn't modified. Errors of invalid options are reported to WithOnError, like
+// options changing how the manifest, which is shared with st, is loaded or looked up, e.g. WithUseMinified.
+func (st *Static) WithRequestOptions(options ...optionSetter) *Static {
+	clone := st.Clone()
+	clone.optionErr = nil
+	settings := clone.mappingSettings()
+	for _, optionSetter := range options {
+		optionSetter(clone)
+	}
+	if clone.mappingSettings() != settings {
+		clone.setOptionErr(errSharedMappingOption)
+	}
+	if clone.optionErr != nil {
+		clone.reportError(clone.optionErr)
+		clone.optionErr = nil
+	}
+	if !strings.HasSuffix(clone.urlPrefix, "/") {
+		clone.urlPrefix += "/"
+	}
+	if clone.dedupTags && clone.rendered == nil {
+		clone.rendered = newRenderedTags()
+	}
+	return clone
+}
+
+// mappingSettings holds the settings applied when the mapping is built, to detect options that change them.
+type mappingSettings struct {
+	manifestPath    string
+	manifestFormat  string
+	manifestParser  string
+	manifestScheme  ManifestScheme
+	useMinified     bool
+	minifiedOnly    bool
+	stripExtension  bool
+	refreshInterval time.Duration
+	cdn             *cdnFailover
+	hasLoader       bool
+	decompress      bool
+	keyNormalizer   bool
+	transforms      int
+	validators      int
+	extraManifests  int
+	pathRewrites    int
+	inferredExts    int
+}
+
+func (st *Static) mappingSettings() mappingSettings {
+	return mappingSettings{
+		manifestPath:    st.manifestPath,
+		manifestFormat:  st.manifestFormat,
+		manifestParser:  fmt.Sprintf("%T", st.manifestParser),
+		manifestScheme:  st.manifestScheme,
+		useMinified:     st.useMinified,
+		minifiedOnly:    st.minifiedOnly,
+		stripExtension:  st.stripExtension,
+		refreshInterval: st.refreshInterval,
+		cdn:             st.cdn,
+		hasLoader:       st.manifestLoader != nil,
+		decompress:      st.decompress != nil,
+		keyNormalizer:   st.keyNormalizer != nil,
+		transforms:      len(st.transforms),
+		validators:      len(st.validators),
+		extraManifests:  len(st.extraManifests),
+		pathRewrites:    len(st.pathRewrites),
+		inferredExts:    len(st.inferredExts),
+	}
+}
+
+func (st *Static) addNonce(attrMap map[string]string) {
+	if st.nonceFunc != nil {
+		if nonce := st.nonceFunc(); nonce != "" {
+			attrMap["nonce"] = nonce
+		}
+	}
+}
+
+// ScriptTagWithNonce returns HTML script tag with the nonce attribute. See ScriptTag for additional information.
+func (st *Static) ScriptTagWithNonce(path string, nonce string, attrs ...string) (template.HTML, error) {
+	return st.ScriptTag(path, append(attrs[:len(attrs):len(attrs)], "nonce", nonce)...)
+}
+
+// CSPNonces returns the unique nonces of the tags emitted by ScriptTag, LinkTag and ScriptTagWithNonce, in
+// the order in which they were emitted, e.g. to build the Content-Security-Policy header after rendering.
+// Nonces are tracked per instance, so Clone should be used to get an instance for every rendered page.
+func (st *Static) CSPNonces() []string {
+	if st.nonces == nil {
+		return nil
+	}
+	st.nonces.mu.Lock()
+	defer st.nonces.mu.Unlock()
+	return append([]string(nil), st.nonces.nonces...)
+}
+
+// recordNonce adds nonce, if not empty, to the nonces returned by CSPNonces.
+func (st *Static) recordNonce(nonce string) {
+	if nonce == "" || st.nonces == nil {
+		return
+	}
+	st.nonces.mu.Lock()
+	defer st.nonces.mu.Unlock()
+	if !st.nonces.seen[nonce] {
+		st.nonces.seen[nonce] = true
+		st.nonces.nonces = append(st.nonces.nonces, nonce)
+	}
+}
@@ -0,0 +1,119 @@
+package asset
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"html/template"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCSPNonces(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil))
+	require.Nil(t, err)
+	require.Empty(t, static.CSPNonces())
+	page := static.Clone()
+	tag, err := page.ScriptTagWithNonce("js/app.js", "abc", "defer", "defer")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<script defer="defer" nonce="abc" src="/static/js/app.js" type="text/javascript"></script>`), tag)
+	_, err = page.LinkTag("css/style.css", "nonce", "def")
+	require.Nil(t, err)
+	_, err = page.ScriptTag("js/other.js", "nonce", "abc")
+	require.Nil(t, err)
+	_, err = page.ScriptTag("js/plain.js")
+	require.Nil(t, err)
+	require.Equal(t, []string{"abc", "def"}, page.CSPNonces())
+	require.Empty(t, static.CSPNonces())
+	require.Empty(t, page.Clone().CSPNonces())
+}
+
+func TestNonce(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil))
+	require.Nil(t, err)
+	page := static.WithRequestOptions(WithNonce("r4nd0m"))
+	tag, err := page.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<script nonce="r4nd0m" src="/static/js/app.js" type="text/javascript"></script>`), tag)
+	tag, err = page.LinkTag("css/style.css", "nonce", "override")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<link href="/static/css/style.css" nonce="override" rel="stylesheet" type="text/css"/>`), tag)
+	require.Equal(t, []string{"r4nd0m", "override"}, page.CSPNonces())
+
+	tag, err = static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<script src="/static/js/app.js" type="text/javascript"></script>`), tag)
+	require.Empty(t, static.CSPNonces())
+}
+
+func TestNonceFunc(t *testing.T) {
+	count := 0
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithNonceFunc(func() string {
+		count++
+		return fmt.Sprintf("n%d", count)
+	}))
+	require.Nil(t, err)
+	first, err := static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	second, err := static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Contains(t, first, `nonce="n1"`)
+	require.Contains(t, second, `nonce="n2"`)
+}
+
+func TestRequestOptionsError(t *testing.T) {
+	var reported error
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithOnError(func(err error) { reported = err }))
+	require.Nil(t, err)
+	page := static.WithRequestOptions(WithStaticPrefix("invalid"))
+	require.NotNil(t, reported)
+	require.Equal(t, "/static/js/app.js", page.URLFor("js/app.js"))
+}
+
+func TestRequestOptionsSetup(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	var reported error
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader),
+		WithOnError(func(err error) { reported = err }))
+	require.Nil(t, err)
+
+	page := static.WithRequestOptions(WithTagDeduplication(true))
+	tag, err := page.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<script src="/static/dist/app-1234.js" type="text/javascript"></script>`), tag)
+	tag, err = page.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(""), tag)
+
+	page = static.WithRequestOptions(WithStaticPrefix("/tenant"))
+	require.Equal(t, "/tenant/dist/app-1234.js", page.URLFor("js/app.js"))
+	require.Nil(t, reported)
+
+	static.WithRequestOptions(WithUseMinified(true))
+	require.Equal(t, errSharedMappingOption, reported)
+	reported = nil
+	static.WithRequestOptions(WithKeyNormalizer(strings.ToLower))
+	require.Equal(t, errSharedMappingOption, reported)
+}
+
+func TestRequestOptionsSriAlgorithm(t *testing.T) {
+	fsys := fstest.MapFS{"js/app.js": {Data: []byte("alert(1)")}}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithFS(fsys), WithUseSri(true))
+	require.Nil(t, err)
+	tag, err := static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Contains(t, tag, `integrity="sha256-`)
+	tag, err = static.WithRequestOptions(WithSriAlgorithm("sha512")).ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Contains(t, tag, `integrity="sha512-`)
+	tag, err = static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Contains(t, tag, `integrity="sha256-`)
+
+	other := fstest.MapFS{"js/app.js": {Data: []byte("alert(2)")}}
+	first, err := static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	second, err := static.WithRequestOptions(WithFS(other)).ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.NotEqual(t, first, second)
+}
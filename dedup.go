@@ -0,0 +1,39 @@
+package asset
+
+import (
+	"sync"
+)
+
+// WithTagDeduplication can be used in NewStatic to emit a tag for every asset only once: subsequent ScriptTag
+// and LinkTag calls with the same path return an empty string, e.g. when the same asset is required by
+// multiple partials. Rendered tags are tracked per page, so only the instances returned by Clone or
+// WithRequestOptions, which should be used for every rendered page, deduplicate tags; the instance returned
+// by NewStatic renders all of them. Tags that failed to render aren't tracked. Disabled by default.
+func WithTagDeduplication(enabled bool) optionSetter {
+	return func(st *Static) { st.dedupTags = enabled }
+}
+
+type renderedTags struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+func newRenderedTags() *renderedTags {
+	return &renderedTags{seen: map[string]bool{}}
+}
+
+// firstRender reports whether a tag of the given kind should be rendered for the asset, marking it as rendered.
+// It's called once the tag is rendered successfully.
+func (st *Static) firstRender(kind string, path string) bool {
+	if st.rendered == nil {
+		return true
+	}
+	st.rendered.mu.Lock()
+	defer st.rendered.mu.Unlock()
+	key := kind + ":" + path
+	if st.rendered.seen[key] {
+		return false
+	}
+	st.rendered.seen[key] = true
+	return true
+}
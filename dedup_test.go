@@ -0,0 +1,94 @@
+package asset
+
+import (
+	"github.com/stretchr/testify/require"
+	"html/template"
+	"testing"
+)
+
+func TestTagDeduplication(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithTagDeduplication(true))
+	require.Nil(t, err)
+	page := static.Clone()
+	tag, err := page.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<script src="/static/js/app.js" type="text/javascript"></script>`), tag)
+	tag, err = page.ScriptTag("js/app.js", "defer", "defer")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(""), tag)
+	tag, err = page.LinkTag("css/style.css")
+	require.Nil(t, err)
+	require.NotEqual(t, template.HTML(""), tag)
+	tag, err = page.LinkTag("css/style.css")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(""), tag)
+
+	page = static.Clone()
+	tag, err = page.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<script src="/static/js/app.js" type="text/javascript"></script>`), tag)
+	tag, err = page.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(""), tag)
+
+	for i := 0; i < 2; i++ {
+		tag, err = static.ScriptTag("js/app.js")
+		require.Nil(t, err)
+		require.NotEqual(t, template.HTML(""), tag)
+	}
+}
+
+func TestTagDeduplicationFailedRender(t *testing.T) {
+	static, err := NewStatic(
+		"/static", "manifest.json", WithManifestLoader(nil), WithTagDeduplication(true), WithStrictMode(true),
+	)
+	require.Nil(t, err)
+	page := static.Clone()
+	_, err = page.ScriptTag("js/app.js")
+	require.NotNil(t, err)
+	static.SwapManifest(mapperFunc(func(name string) string { return "dist/" + name }))
+	tag, err := page.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<script src="/static/dist/js/app.js" type="text/javascript"></script>`), tag)
+}
+
+func TestTagDeduplicationDisabled(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil))
+	require.Nil(t, err)
+	for i := 0; i < 2; i++ {
+		tag, err := static.ScriptTag("js/app.js")
+		require.Nil(t, err)
+		require.NotEqual(t, template.HTML(""), tag)
+	}
+}
+
+func TestCloneSharesManifest(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil))
+	require.Nil(t, err)
+	clone := static.Clone().Clone()
+	static.SwapManifest(mapperFunc(func(name string) string { return "dist/" + name }))
+	require.Equal(t, "/static/dist/js/app.js", clone.URLFor("js/app.js"))
+	clone.SwapManifest(mapperFunc(func(name string) string { return "cdn/" + name }))
+	require.Equal(t, "/static/cdn/js/app.js", static.URLFor("js/app.js"))
+}
+
+func TestCloneAttachFuncs(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil))
+	require.Nil(t, err)
+	static.AttachFuncs(template.FuncMap{"one": func() string { return "1" }})
+	clone := static.Clone().AttachFuncs(template.FuncMap{"two": func() string { return "2" }})
+	require.Contains(t, clone.FuncMap(), "one")
+	require.Contains(t, clone.FuncMap(), "two")
+	require.NotContains(t, static.FuncMap(), "two")
+}
+
+func TestCloneWithPrefix(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	tenant := static.CloneWithPrefix("https://cdn.example.com/tenant")
+	require.Equal(t, "https://cdn.example.com/tenant/dist/app-1234.js", tenant.URLFor("js/app.js"))
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+	static.SwapManifest(mapperFunc(func(name string) string { return "cdn/" + name }))
+	require.Equal(t, "https://cdn.example.com/tenant/cdn/js/app.js", tenant.URLFor("js/app.js"))
+}
@@ -0,0 +1,30 @@
+// Package echo integrates go-asset-helper with the Echo framework.
+package echo
+
+import (
+	labstack "github.com/labstack/echo/v4"
+	"github.com/rsniezynski/go-asset-helper"
+	"html/template"
+	"io"
+)
+
+// Renderer is an Echo renderer executing templates with the go-asset-helper functions attached.
+type Renderer struct {
+	templates *template.Template
+}
+
+// Render implements echo.Renderer.
+func (r *Renderer) Render(w io.Writer, name string, data interface{}, c labstack.Context) error {
+	return r.templates.ExecuteTemplate(w, name, data)
+}
+
+// AttachEcho parses the templates matching pattern with the functions of st attached and sets them
+// as the renderer of e.
+func AttachEcho(e *labstack.Echo, st *asset.Static, pattern string) error {
+	templates, err := st.Template().ParseGlob(pattern)
+	if err != nil {
+		return err
+	}
+	e.Renderer = &Renderer{templates}
+	return nil
+}
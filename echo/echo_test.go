@@ -0,0 +1,27 @@
+package echo
+
+import (
+	labstack "github.com/labstack/echo/v4"
+	"github.com/rsniezynski/go-asset-helper"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestAttachEcho(t *testing.T) {
+	dir := t.TempDir()
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "page.html"), []byte(`{{ scripttag . }}`), 0644))
+	static, err := asset.NewStatic("/static", "manifest.json", asset.WithManifestLoader(nil))
+	require.Nil(t, err)
+	e := labstack.New()
+	require.Nil(t, AttachEcho(e, static, filepath.Join(dir, "*.html")))
+	e.GET("/", func(c labstack.Context) error { return c.Render(http.StatusOK, "page.html", "js/app.js") })
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+	require.Equal(t, `<script src="/static/js/app.js" type="text/javascript"></script>`, recorder.Body.String())
+
+	require.NotNil(t, AttachEcho(e, static, filepath.Join(dir, "*.missing")))
+}
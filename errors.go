@@ -0,0 +1,117 @@
+package asset
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrAssetNotFound is returned by tag functions with WithStrictMode when an asset isn't in the manifest.
+var ErrAssetNotFound = errors.New("asset not found in the manifest")
+
+// ErrInvalidManifest is matched by errors.Is for manifests that can't be parsed or aren't JSON objects.
+var ErrInvalidManifest = errors.New("invalid manifest")
+
+// invalidManifestError keeps the message and the cause, e.g. *json.SyntaxError, while matching ErrInvalidManifest.
+type invalidManifestError struct {
+	err error
+}
+
+func (e *invalidManifestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *invalidManifestError) Unwrap() error {
+	return e.err
+}
+
+func (e *invalidManifestError) Is(target error) bool {
+	return target == ErrInvalidManifest
+}
+
+// AssetError is returned when WithTypedErrors is enabled and rendering a tag for an asset fails.
+type AssetError struct {
+	Path string
+	Err  error
+}
+
+func (e *AssetError) Error() string {
+	return fmt.Sprintf("asset %s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *AssetError) Unwrap() error {
+	return e.Err
+}
+
+// ManifestError is returned when WithTypedErrors is enabled and loading the manifest fails.
+type ManifestError struct {
+	Path string
+	Err  error
+}
+
+func (e *ManifestError) Error() string {
+	return fmt.Sprintf("manifest %s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ManifestError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError is a list of errors returned by functions checking multiple assets at once.
+type MultiError []error
+
+func (e MultiError) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the listed errors.
+func (e MultiError) Unwrap() []error {
+	return e
+}
+
+// WithTypedErrors can be used in NewStatic to wrap errors returned by NewStatic, ScriptTag and LinkTag,
+// and passed to reload callbacks, in AssetError or ManifestError, so that they can be inspected with
+// errors.As. Disabled by default, in which case errors e.g. from the loader or JSON parsing are returned as is.
+func WithTypedErrors(enabled bool) optionSetter {
+	return func(st *Static) { st.typedErrors = enabled }
+}
+
+// WithOnError can be used in NewStatic to provide a function receiving problems that don't make any call
+// fail, e.g. assets exceeding the size limit with LimitActionWarn.
+func WithOnError(fn func(err error)) optionSetter {
+	return func(st *Static) { st.onError = fn }
+}
+
+func (st *Static) reportError(err error) {
+	if st.onError != nil {
+		st.onError(err)
+	}
+}
+
+// assetError wraps err in AssetError if typed errors are enabled.
+func (st *Static) assetError(path string, err error) error {
+	if err == nil || !st.typedErrors || isTyped(err) {
+		return err
+	}
+	return &AssetError{Path: path, Err: err}
+}
+
+// manifestError wraps err in ManifestError if typed errors are enabled.
+func (st *Static) manifestError(err error) error {
+	if err == nil || !st.typedErrors || isTyped(err) {
+		return err
+	}
+	return &ManifestError{Path: st.manifestPath, Err: err}
+}
+
+func isTyped(err error) bool {
+	var assetErr *AssetError
+	var manifestErr *ManifestError
+	return errors.As(err, &assetErr) || errors.As(err, &manifestErr)
+}
@@ -0,0 +1,63 @@
+package asset
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTypedErrorsNewStatic(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte("garbage"), nil }
+	_, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithTypedErrors(true))
+	var manifestErr *ManifestError
+	require.True(t, errors.As(err, &manifestErr))
+	require.Equal(t, "manifest.json", manifestErr.Path)
+	var syntaxErr *json.SyntaxError
+	require.True(t, errors.As(err, &syntaxErr))
+
+	_, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.True(t, errors.As(err, &syntaxErr))
+	require.False(t, errors.As(err, &manifestErr))
+}
+
+func TestTypedErrorsOption(t *testing.T) {
+	_, err := NewStatic("/static", "manifest.json", WithManifestKeyRegexp("("), WithTypedErrors(true))
+	var manifestErr *ManifestError
+	require.True(t, errors.As(err, &manifestErr))
+}
+
+func TestTypedErrorsTags(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithTypedErrors(true))
+	require.Nil(t, err)
+	_, err = static.ScriptTag("js/app.js", "defer")
+	var assetErr *AssetError
+	require.True(t, errors.As(err, &assetErr))
+	require.Equal(t, "js/app.js", assetErr.Path)
+	_, err = static.LinkTag("css/style.css", "media")
+	require.True(t, errors.As(err, &assetErr))
+	require.Equal(t, "css/style.css", assetErr.Path)
+}
+
+func TestTypedErrorsNotWrappedTwice(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithTypedErrors(true))
+	require.Nil(t, err)
+	wrapped := &ManifestError{Path: "manifest.json", Err: errManifestNotObject}
+	require.Equal(t, wrapped, static.manifestError(wrapped))
+	require.Equal(t, wrapped, static.assetError("js/app.js", wrapped))
+}
+
+func TestErrInvalidManifest(t *testing.T) {
+	for _, content := range []string{"garbage", `["js/app.js"]`, `"js/app.js"`, `null`} {
+		content := content
+		loader := func(name string) ([]byte, error) { return []byte(content), nil }
+		_, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+		require.True(t, errors.Is(err, ErrInvalidManifest), content)
+		require.False(t, errors.Is(err, ErrAssetNotFound), content)
+		_, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithTypedErrors(true))
+		require.True(t, errors.Is(err, ErrInvalidManifest), content)
+	}
+	loader := func(name string) ([]byte, error) { return nil, errors.New("read error") }
+	_, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.False(t, errors.Is(err, ErrInvalidManifest))
+}
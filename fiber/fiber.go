@@ -0,0 +1,49 @@
+// Package fiber integrates go-asset-helper with the Fiber framework. Views implements fiber.Views without
+// importing Fiber.
+package fiber
+
+import (
+	"errors"
+	"github.com/rsniezynski/go-asset-helper"
+	"html/template"
+	"io"
+)
+
+// Views is a fiber.Views engine executing templates with the go-asset-helper functions attached.
+type Views struct {
+	static    *asset.Static
+	pattern   string
+	templates *template.Template
+}
+
+// AttachFiber returns Views loading the templates matching pattern, to be used as Views in fiber.Config.
+func AttachFiber(st *asset.Static, pattern string) *Views {
+	return &Views{static: st, pattern: pattern}
+}
+
+// Load parses the templates with the functions of the Static instance attached. Called by Fiber on start.
+func (v *Views) Load() error {
+	templates, err := v.static.Template().ParseGlob(v.pattern)
+	if err != nil {
+		return err
+	}
+	v.templates = templates
+	return nil
+}
+
+// Render executes the template with the given name. Layouts aren't supported; use template composition
+// ({{ template "name" . }}) instead.
+func (v *Views) Render(w io.Writer, name string, data interface{}, layouts ...string) error {
+	if len(layouts) > 0 && layouts[0] != "" {
+		return errLayoutsNotSupported
+	}
+	if v.templates == nil {
+		return errNotLoaded
+	}
+	return v.templates.ExecuteTemplate(w, name, data)
+}
+
+var (
+	errLayoutsNotSupported = errors.New("layouts are not supported")
+	errNotLoaded           = errors.New("views are not loaded")
+)
@@ -0,0 +1,24 @@
+package fiber
+
+import (
+	"github.com/rsniezynski/go-asset-helper"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAttachFiber(t *testing.T) {
+	dir := t.TempDir()
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "page.html"), []byte(`{{ linktag . }}`), 0644))
+	static, err := asset.NewStatic("/static", "manifest.json", asset.WithManifestLoader(nil))
+	require.Nil(t, err)
+	views := AttachFiber(static, filepath.Join(dir, "*.html"))
+	var out strings.Builder
+	require.Equal(t, errNotLoaded, views.Render(&out, "page.html", "css/style.css"))
+	require.Nil(t, views.Load())
+	require.Nil(t, views.Render(&out, "page.html", "css/style.css"))
+	require.Equal(t, `<link href="/static/css/style.css" rel="stylesheet" type="text/css"/>`, out.String())
+	require.Equal(t, errLayoutsNotSupported, views.Render(&out, "page.html", nil, "layout.html"))
+}
@@ -0,0 +1,60 @@
+package asset
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// OutputFormat selects the markup style of the tags emitted by ScriptTag and LinkTag.
+type OutputFormat int
+
+const (
+	// OutputFormatHTML5 emits HTML5 tags. This is the default.
+	OutputFormatHTML5 OutputFormat = iota
+	// OutputFormatXHTML emits XHTML tags, with self-closing script tags.
+	OutputFormatXHTML
+	// OutputFormatAMP emits AMP-compliant tags: scripts are emitted as amp-script elements, which require
+	// the layout attribute, and external stylesheets, which AMP doesn't allow, make LinkTag return an error.
+	OutputFormatAMP
+)
+
+// WithOutputFormat can be used in NewStatic to select the markup style of the emitted tags.
+func WithOutputFormat(format OutputFormat) optionSetter {
+	return func(st *Static) { st.outputFormat = format }
+}
+
+// formatScriptTag returns a script tag with attrs and the boolean attributes, which are rendered without values,
+// in the selected output format.
+func (st *Static) formatScriptTag(attrMap map[string]string, boolAttrs ...string) (string, error) {
+	if st.outputFormat == OutputFormatAMP {
+		if _, ok := attrMap["layout"]; !ok {
+			return "", errAMPLayoutRequired
+		}
+		delete(attrMap, "type")
+	}
+	attrs := mapToAttrs(attrMap, !st.disableEscaping)
+	if len(boolAttrs) > 0 {
+		attrs = strings.Join(boolAttrs, " ") + " " + attrs
+	}
+	switch st.outputFormat {
+	case OutputFormatXHTML:
+		return fmt.Sprintf(`<script %s/>`, attrs), nil
+	case OutputFormatAMP:
+		return fmt.Sprintf(`<amp-script %s></amp-script>`, attrs), nil
+	}
+	return fmt.Sprintf(`<script %s></script>`, attrs), nil
+}
+
+// formatLinkTag returns a link tag with attrs in the selected output format.
+func (st *Static) formatLinkTag(attrMap map[string]string) (string, error) {
+	if st.outputFormat == OutputFormatAMP {
+		return "", errAMPStylesheet
+	}
+	return fmt.Sprintf(`<link %s/>`, mapToAttrs(attrMap, !st.disableEscaping)), nil
+}
+
+var (
+	errAMPLayoutRequired = errors.New("amp-script requires the layout attribute")
+	errAMPStylesheet     = errors.New("external stylesheets are not allowed in AMP")
+)
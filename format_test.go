@@ -0,0 +1,30 @@
+package asset
+
+import (
+	"github.com/stretchr/testify/require"
+	"html/template"
+	"testing"
+)
+
+func TestOutputFormatXHTML(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithOutputFormat(OutputFormatXHTML))
+	require.Nil(t, err)
+	tag, err := static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<script src="/static/js/app.js" type="text/javascript"/>`), tag)
+	tag, err = static.LinkTag("css/style.css")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<link href="/static/css/style.css" rel="stylesheet" type="text/css"/>`), tag)
+}
+
+func TestOutputFormatAMP(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithOutputFormat(OutputFormatAMP))
+	require.Nil(t, err)
+	tag, err := static.ScriptTag("js/app.js", "layout", "container")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<amp-script layout="container" src="/static/js/app.js"></amp-script>`), tag)
+	_, err = static.ScriptTag("js/app.js")
+	require.Equal(t, errAMPLayoutRequired, err)
+	_, err = static.LinkTag("css/style.css")
+	require.Equal(t, errAMPStylesheet, err)
+}
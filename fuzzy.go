@@ -0,0 +1,79 @@
+package asset
+
+import (
+	"fmt"
+)
+
+// WithFuzzyMatch can be used in NewStatic to help finding mistyped asset paths during development. When a path
+// isn't found in the manifest, the most similar manifest key is looked up and, if its similarity (1 minus
+// the Levenshtein distance divided by the length of the longer string) is above threshold, a suggestion is
+// passed to the function given with WithOnError. The path is still resolved as usual. threshold must be
+// in [0, 1]; otherwise NewStatic returns an error.
+func WithFuzzyMatch(threshold float64) optionSetter {
+	return func(st *Static) {
+		if threshold < 0 || threshold > 1 {
+			st.setOptionErr(fmt.Errorf("fuzzy match threshold %v is not in [0, 1]", threshold))
+			return
+		}
+		st.fuzzyThreshold = threshold
+		st.fuzzyMatch = true
+	}
+}
+
+// suggest reports the manifest key most similar to a path that wasn't found.
+func (st *Static) suggest(path string) {
+	mapping, ok := st.currentMapping().(*staticMap)
+	if !ok || mapping.Has(path) {
+		return
+	}
+	best, bestSimilarity := "", 0.0
+	for _, key := range mapping.keys() {
+		if key == path {
+			continue
+		}
+		if similarity := similarity(path, key); similarity > bestSimilarity || similarity == bestSimilarity && key < best {
+			best, bestSimilarity = key, similarity
+		}
+	}
+	if best != "" && bestSimilarity > st.fuzzyThreshold {
+		st.reportError(fmt.Errorf("asset %s not found in the manifest, did you mean %s?", path, best))
+	}
+}
+
+func similarity(a, b string) float64 {
+	ar, br := []rune(a), []rune(b)
+	longer := len(ar)
+	if len(br) > longer {
+		longer = len(br)
+	}
+	if longer == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ar, br))/float64(longer)
+}
+
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,36 @@
+package asset
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	require.Equal(t, 0, levenshtein([]rune("app.js"), []rune("app.js")))
+	require.Equal(t, 3, levenshtein([]rune("kitten"), []rune("sitting")))
+	require.Equal(t, 4, levenshtein([]rune(""), []rune("abcd")))
+	require.Equal(t, 1.0, similarity("", ""))
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"dist/app-1234.js", "css/style.css":"dist/style-1234.css",
+			"favicon.ico":"favicon.ico"}`), nil
+	}
+	var warnings []string
+	static, err := NewStatic(
+		"/static", "manifest.json", WithManifestLoader(loader), WithFuzzyMatch(0.8),
+		WithOnError(func(err error) { warnings = append(warnings, err.Error()) }),
+	)
+	require.Nil(t, err)
+	require.Equal(t, "/static/js/ap.js", static.URLFor("js/ap.js"))
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+	require.Equal(t, "/static/img/logo.png", static.URLFor("img/logo.png"))
+	require.Equal(t, "/static/favicon.ico", static.URLFor("favicon.ico"))
+	require.Equal(t, []string{"asset js/ap.js not found in the manifest, did you mean js/app.js?"}, warnings)
+}
+
+func TestFuzzyMatchInvalidThreshold(t *testing.T) {
+	_, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithFuzzyMatch(1.5))
+	require.NotNil(t, err)
+}
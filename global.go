@@ -0,0 +1,44 @@
+package asset
+
+import (
+	"html/template"
+	"sync"
+)
+
+var (
+	globalMu sync.Mutex
+	global   *Static
+)
+
+// SetGlobal sets the Static instance used by the package-level functions.
+func SetGlobal(st *Static) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	global = st
+}
+
+// Global returns the Static instance used by the package-level functions. It panics if none was set
+// with SetGlobal or WithGlobalInstance.
+func Global() *Static {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	if global == nil {
+		panic("asset: global Static is not set, use SetGlobal or WithGlobalInstance")
+	}
+	return global
+}
+
+// WithGlobalInstance can be used in NewStatic to make the created instance the global one, see SetGlobal.
+func WithGlobalInstance() optionSetter {
+	return func(st *Static) { st.setGlobal = true }
+}
+
+// ScriptTag calls ScriptTag of the global Static instance.
+func ScriptTag(path string, attrs ...string) (template.HTML, error) {
+	return Global().ScriptTag(path, attrs...)
+}
+
+// LinkTag calls LinkTag of the global Static instance.
+func LinkTag(path string, attrs ...string) (template.HTML, error) {
+	return Global().LinkTag(path, attrs...)
+}
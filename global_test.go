@@ -0,0 +1,26 @@
+package asset
+
+import (
+	"github.com/stretchr/testify/require"
+	"html/template"
+	"testing"
+)
+
+func TestGlobal(t *testing.T) {
+	defer SetGlobal(nil)
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithGlobalInstance())
+	require.Nil(t, err)
+	require.Equal(t, static, Global())
+	tag, err := ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<script src="/static/dist/app-1234.js" type="text/javascript"></script>`), tag)
+	tag, err = LinkTag("css/style.css")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<link href="/static/css/style.css" rel="stylesheet" type="text/css"/>`), tag)
+}
+
+func TestGlobalNotSet(t *testing.T) {
+	SetGlobal(nil)
+	require.Panics(t, func() { Global() })
+}
@@ -0,0 +1,34 @@
+// Package gorilla integrates go-asset-helper with the Gorilla mux router.
+package gorilla
+
+import (
+	"context"
+	"github.com/gorilla/mux"
+	"github.com/rsniezynski/go-asset-helper"
+	"html/template"
+	"net/http"
+)
+
+type templatesKey struct{}
+
+// AttachGorilla parses the templates matching pattern with the functions of st attached and adds a middleware
+// to r making them available to the handlers through Templates. As Gorilla has no renderer, the handlers
+// execute the templates themselves. Gorilla runs middlewares only for matched routes.
+func AttachGorilla(r *mux.Router, st *asset.Static, pattern string) error {
+	templates, err := st.Template().ParseGlob(pattern)
+	if err != nil {
+		return err
+	}
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), templatesKey{}, templates)))
+		})
+	})
+	return nil
+}
+
+// Templates returns the templates attached with AttachGorilla to the router handling req, or nil.
+func Templates(req *http.Request) *template.Template {
+	templates, _ := req.Context().Value(templatesKey{}).(*template.Template)
+	return templates
+}
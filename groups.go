@@ -0,0 +1,116 @@
+package asset
+
+import (
+	"html/template"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Group is a named list of assets that can be emitted together with GroupScriptTags and GroupLinkTags.
+type Group struct {
+	Name  string
+	Paths []string
+}
+
+// WithGroups can be used in NewStatic to define asset groups. Paths keep the order in which they're given.
+func WithGroups(groups ...Group) optionSetter {
+	return func(st *Static) { st.groups = append(st.groups, groups...) }
+}
+
+// WithGroupBy can be used in NewStatic to assign manifest entries to groups automatically: categorizer
+// is called with every manifest key and returns the name of its group, or "" to leave it ungrouped.
+// Automatically categorized entries follow the manually defined group members, sorted by key.
+func WithGroupBy(categorizer func(key string) string) optionSetter {
+	return func(st *Static) { st.groupBy = categorizer }
+}
+
+// GroupScriptTags returns script tags for all the JavaScript (.js) assets of a group, separated by
+// newlines. Usually not used directly, but registered in template via FuncMap.
+func (st *Static) GroupScriptTags(name string) (template.HTML, error) {
+	return st.groupTags(name, ".js", st.ScriptTag)
+}
+
+// GroupLinkTags returns link tags for all the stylesheet (.css) assets of a group, separated by newlines.
+// Usually not used directly, but registered in template via FuncMap.
+func (st *Static) GroupLinkTags(name string) (template.HTML, error) {
+	return st.groupTags(name, ".css", st.LinkTag)
+}
+
+func (st *Static) groupTags(name string, ext string, tagFunc func(string, ...string) (template.HTML, error)) (template.HTML, error) {
+	paths := []string{}
+	for _, assetPath := range st.groupPaths(name) {
+		if path.Ext(assetPath) == ext {
+			paths = append(paths, assetPath)
+		}
+	}
+	return joinTags(paths, tagFunc)
+}
+
+// ScriptTags returns script tags for all the paths, separated by newlines. Usually not used directly, but
+// registered in template via FuncMap.
+func (st *Static) ScriptTags(paths ...string) (template.HTML, error) {
+	return joinTags(paths, st.ScriptTag)
+}
+
+// LinkTags returns link tags for all the paths, separated by newlines. Usually not used directly, but
+// registered in template via FuncMap.
+func (st *Static) LinkTags(paths ...string) (template.HTML, error) {
+	return joinTags(paths, st.LinkTag)
+}
+
+// joinTags returns the tags of all the paths separated by newlines, or the first error. Empty tags, e.g. of
+// duplicates with WithTagDeduplication, are skipped.
+func joinTags(paths []string, tagFunc func(string, ...string) (template.HTML, error)) (template.HTML, error) {
+	tags := make([]string, 0, len(paths))
+	for _, assetPath := range paths {
+		tag, err := tagFunc(assetPath)
+		if err != nil {
+			return "", err
+		}
+		if tag != "" {
+			tags = append(tags, string(tag))
+		}
+	}
+	return template.HTML(strings.Join(tags, "\n")), nil
+}
+
+// groupPaths returns manually defined members of a group followed by the automatically categorized ones.
+func (st *Static) groupPaths(name string) []string {
+	paths := []string{}
+	seen := map[string]bool{}
+	for _, group := range st.groups {
+		if group.Name != name {
+			continue
+		}
+		for _, assetPath := range group.Paths {
+			if !seen[assetPath] {
+				seen[assetPath] = true
+				paths = append(paths, assetPath)
+			}
+		}
+	}
+	mapping, ok := st.currentMapping().(*staticMap)
+	if st.groupBy == nil || !ok || name == "" {
+		return paths
+	}
+	keys := []string{}
+	for _, key := range mapping.keys() {
+		if !seen[key] && !isMinifiedVariant(mapping.innerMap, key) && st.groupBy(key) == name {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return append(paths, keys...)
+}
+
+// isMinifiedVariant reports whether key is the minified name of another manifest key.
+func isMinifiedVariant(manifest map[string]string, key string) bool {
+	ext := path.Ext(key)
+	base := strings.TrimSuffix(key, ext)
+	if !strings.HasSuffix(base, ".min") {
+		return false
+	}
+	_, ok := manifest[strings.TrimSuffix(base, ".min")+ext]
+	return ok
+}
@@ -0,0 +1,91 @@
+package asset
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+func TestGroupTags(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"dist/app-1234.js", "css/app.css":"dist/app-1234.css"}`), nil
+	}
+	static, err := NewStatic(
+		"/static", "manifest.json", WithManifestLoader(loader),
+		WithGroups(Group{Name: "main", Paths: []string{"js/vendor.js", "css/app.css", "js/app.js"}}),
+	)
+	require.Nil(t, err)
+	tags, err := static.GroupScriptTags("main")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<script src="/static/js/vendor.js" type="text/javascript"></script>`+"\n"+
+			`<script src="/static/dist/app-1234.js" type="text/javascript"></script>`,
+	), tags)
+	tags, err = static.GroupLinkTags("main")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<link href="/static/dist/app-1234.css" rel="stylesheet" type="text/css"/>`), tags)
+	tags, err = static.GroupLinkTags("missing")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(""), tags)
+}
+
+func TestGroupBy(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{
+			"admin/b.js":"dist/b-1234.js", "admin/a.js":"dist/a-1234.js", "admin/a.min.js":"dist/a-1234.min.js",
+			"js/app.js":"dist/app-1234.js"
+		}`), nil
+	}
+	categorizer := func(key string) string {
+		if strings.HasPrefix(key, "admin/") {
+			return "admin"
+		}
+		return ""
+	}
+	static, err := NewStatic(
+		"/static", "manifest.json", WithManifestLoader(loader), WithUseMinified(true), WithGroupBy(categorizer),
+	)
+	require.Nil(t, err)
+	tags, err := static.GroupScriptTags("admin")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<script src="/static/dist/a-1234.min.js" type="text/javascript"></script>`+"\n"+
+			`<script src="/static/dist/b-1234.js" type="text/javascript"></script>`,
+	), tags)
+	tags, err = static.GroupScriptTags("")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(""), tags)
+}
+
+func TestScriptTagsAndLinkTags(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	tmpl, err := static.Template().Parse(`{{ scripttags "js/vendor.js" "js/app.js" }}|{{ linktags "css/a.css" "css/b.css" }}`)
+	require.Nil(t, err)
+	var out strings.Builder
+	require.Nil(t, tmpl.Execute(&out, nil))
+	require.Equal(t, `<script src="/static/js/vendor.js" type="text/javascript"></script>`+"\n"+
+		`<script src="/static/dist/app-1234.js" type="text/javascript"></script>|`+
+		`<link href="/static/css/a.css" rel="stylesheet" type="text/css"/>`+"\n"+
+		`<link href="/static/css/b.css" rel="stylesheet" type="text/css"/>`, out.String())
+
+	static, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithStrictMode(true))
+	require.Nil(t, err)
+	_, err = static.ScriptTags("js/app.js", "js/missing.js")
+	require.True(t, errors.Is(err, ErrAssetNotFound))
+	_, err = static.LinkTags("css/missing.css")
+	require.True(t, errors.Is(err, ErrAssetNotFound))
+	tags, err := static.ScriptTags()
+	require.Nil(t, err)
+	require.Empty(t, tags)
+
+	static, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithTagDeduplication(true))
+	require.Nil(t, err)
+	tags, err = static.Clone().ScriptTags("js/app.js", "js/app.js", "js/other.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<script src="/static/dist/app-1234.js" type="text/javascript"></script>`+"\n"+
+		`<script src="/static/js/other.js" type="text/javascript"></script>`), tags)
+}
@@ -0,0 +1,51 @@
+package asset
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+)
+
+// WithAssetHashing can be used in NewStatic to version assets when there's no manifest: if the loader is nil
+// or the manifest file doesn't exist, every file of the file system given with WithFS, which is required then,
+// is hashed with hashFn and the manifest maps its path to the path with the hash added as the "v" query parameter. If hashFn is nil, the first 8 hex characters of the SHA-256 hash are used, like gulp-rev.
+func WithAssetHashing(hashFn func([]byte) string) optionSetter {
+	return func(st *Static) {
+		if hashFn == nil {
+			hashFn = defaultAssetHash
+		}
+		st.assetHash = hashFn
+	}
+}
+
+func defaultAssetHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])[:8]
+}
+
+// hashAssets creates the mapping from the hashes of all the asset files.
+func (st *Static) hashAssets() (StaticMapper, error) {
+	if st.fsys == nil {
+		return nil, errNoAssetFS
+	}
+	manifest := map[string]string{}
+	fsys := st.fsys
+	err := fs.WalkDir(fsys, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+		content, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		manifest[path] = path + "?v=" + st.assetHash(content)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return st.mappingFromMap(manifest), nil
+}
+
+var errNoAssetFS = errors.New("WithAssetHashing requires WithFS when there's no manifest")
@@ -0,0 +1,57 @@
+package asset
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAssetHashing(t *testing.T) {
+	fsys := fstest.MapFS{
+		"js/app.js":     {Data: []byte("alert(1)")},
+		"css/style.css": {Data: []byte("body {}")},
+	}
+	static, err := NewStatic(
+		"/static", filepath.Join(t.TempDir(), "manifest.json"), WithFS(fsys), WithAssetHashing(nil),
+	)
+	require.Nil(t, err)
+	require.Equal(t, "/static/js/app.js?v=6e11c72f", static.URLFor("js/app.js"))
+	require.Equal(t, "/static/img/logo.png", static.URLFor("img/logo.png"))
+
+	hashFn := func(content []byte) string { return "custom" }
+	static, err = NewStatic("/static", "", WithManifestLoader(nil), WithFS(fsys), WithAssetHashing(hashFn))
+	require.Nil(t, err)
+	require.Equal(t, "/static/css/style.css?v=custom", static.URLFor("css/style.css"))
+}
+
+func TestAssetHashingWithManifest(t *testing.T) {
+	fsys := fstest.MapFS{"js/app.js": {Data: []byte("alert(1)")}}
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithFS(fsys), WithAssetHashing(nil))
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+}
+
+func TestAssetHashingManifestOptions(t *testing.T) {
+	fsys := fstest.MapFS{"js/app.js": {Data: []byte("alert(1)")}}
+	gunzip := func(content []byte) ([]byte, error) { return nil, errors.New("not compressed") }
+	static, err := NewStatic(
+		"/static", "", WithManifestLoader(nil), WithFS(fsys), WithAssetHashing(nil), WithManifestCompression(gunzip),
+	)
+	require.Nil(t, err)
+	require.Equal(t, "/static/js/app.js?v=6e11c72f", static.URLFor("js/app.js"))
+
+	static, err = NewStatic(
+		"/static", "", WithManifestLoader(nil), WithFS(fsys), WithAssetHashing(nil),
+		WithManifestFormat(ManifestFormatWebpackStats),
+	)
+	require.Nil(t, err)
+	require.Equal(t, "/static/js/app.js?v=6e11c72f", static.URLFor("js/app.js"))
+}
+
+func TestAssetHashingWithoutFS(t *testing.T) {
+	_, err := NewStatic("/static", "", WithManifestLoader(nil), WithAssetHashing(nil))
+	require.True(t, errors.Is(err, errNoAssetFS))
+}
@@ -0,0 +1,64 @@
+package asset
+
+import (
+	"html/template"
+	"path"
+	"strings"
+)
+
+// EmitHTMLHead returns the tags of all the registered assets, separated by newlines: link tags for the
+// stylesheets of groups defined with WithGroups, then the hints registered with WithResourceHints, then
+// deferred script tags for the JavaScript assets of the groups. Assets keep the registration order.
+// Usually not used directly, but registered in template via FuncMap.
+func (st *Static) EmitHTMLHead() (template.HTML, error) {
+	paths := st.registeredPaths()
+	tags := []string{}
+	for _, assetPath := range paths {
+		if path.Ext(assetPath) != ".css" {
+			continue
+		}
+		tag, err := st.LinkTag(assetPath)
+		if err != nil {
+			return "", err
+		}
+		tags = append(tags, string(tag))
+	}
+	if len(st.hints) > 0 {
+		hints, err := st.ResourceHints()
+		if err != nil {
+			return "", err
+		}
+		tags = append(tags, string(hints))
+	}
+	for _, assetPath := range paths {
+		if path.Ext(assetPath) != ".js" {
+			continue
+		}
+		tag, err := st.ScriptTag(assetPath, "defer", "defer")
+		if err != nil {
+			return "", err
+		}
+		tags = append(tags, string(tag))
+	}
+	return template.HTML(strings.Join(tags, "\n")), nil
+}
+
+// registeredPaths returns the unique members of all the groups defined with WithGroups, in order.
+func (st *Static) registeredPaths() []string {
+	paths := []string{}
+	seen := map[string]bool{}
+	seenGroups := map[string]bool{}
+	for _, group := range st.groups {
+		if seenGroups[group.Name] {
+			continue
+		}
+		seenGroups[group.Name] = true
+		for _, assetPath := range st.groupPaths(group.Name) {
+			if !seen[assetPath] {
+				seen[assetPath] = true
+				paths = append(paths, assetPath)
+			}
+		}
+	}
+	return paths
+}
@@ -0,0 +1,38 @@
+package asset
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestEmitHTMLHead(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader),
+		WithGroups(
+			Group{Name: "main", Paths: []string{"js/vendor.js", "css/main.css", "js/app.js"}},
+			Group{Name: "admin", Paths: []string{"css/admin.css", "js/app.js"}},
+		),
+		WithResourceHints([]ResourceHint{{Rel: "preload", Path: "fonts/font.woff2", As: "font"}}),
+	)
+	require.Nil(t, err)
+	tmpl, err := static.Template().Parse(`{{ emithead }}`)
+	require.Nil(t, err)
+	var out strings.Builder
+	require.Nil(t, tmpl.Execute(&out, nil))
+	require.Equal(t, strings.Join([]string{
+		`<link href="/static/css/main.css" rel="stylesheet" type="text/css"/>`,
+		`<link href="/static/css/admin.css" rel="stylesheet" type="text/css"/>`,
+		`<link as="font" href="/static/fonts/font.woff2" rel="preload"/>`,
+		`<script defer="defer" src="/static/js/vendor.js" type="text/javascript"></script>`,
+		`<script defer="defer" src="/static/dist/app-1234.js" type="text/javascript"></script>`,
+	}, "\n"), out.String())
+}
+
+func TestEmitHTMLHeadEmpty(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil))
+	require.Nil(t, err)
+	head, err := static.EmitHTMLHead()
+	require.Nil(t, err)
+	require.Empty(t, head)
+}
@@ -0,0 +1,85 @@
+package asset
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// ResourceHint describes a resource hint registered with WithResourceHints.
+type ResourceHint struct {
+	// Rel is the hint type: "preload", "prefetch", "modulepreload", "preconnect" or "dns-prefetch".
+	Rel string
+	// Path is the asset path, resolved through the manifest, or the origin for preconnect and dns-prefetch.
+	Path string
+	// As is the type of the asset, e.g. "script", "style" or "font"; required for preload.
+	As string
+	// Crossorigin is the CORS setting, "anonymous" or "use-credentials"; omitted if empty.
+	Crossorigin string
+}
+
+// resourceHintRels maps supported hint types to whether their paths are resolved through the manifest.
+var resourceHintRels = map[string]bool{
+	"preload":       true,
+	"prefetch":      true,
+	"modulepreload": true,
+	"preconnect":    false,
+	"dns-prefetch":  false,
+}
+
+// WithResourceHints can be used in NewStatic to register resource hints emitted by ResourceHints, e.g. in
+// layouts where the full set of hints is known at startup. Invalid hints make NewStatic return an error.
+func WithResourceHints(hints []ResourceHint) optionSetter {
+	return func(st *Static) {
+		for _, hint := range hints {
+			if _, ok := resourceHintRels[hint.Rel]; !ok {
+				st.setOptionErr(fmt.Errorf("unsupported resource hint %q", hint.Rel))
+				return
+			}
+			if hint.Rel == "preload" && hint.As == "" {
+				st.setOptionErr(fmt.Errorf("resource hint %q: %w", hint.Path, errPreloadAsRequired))
+				return
+			}
+		}
+		st.hints = append(st.hints, hints...)
+	}
+}
+
+// ResourceHints returns link tags for all the hints registered with WithResourceHints, separated by newlines.
+func (st *Static) ResourceHints() (template.HTML, error) {
+	tags := make([]string, len(st.hints))
+	for i, hint := range st.hints {
+		attrMap := map[string]string{"rel": hint.Rel, "href": hint.Path}
+		st.addBuildTimestamp(attrMap)
+		if resourceHintRels[hint.Rel] {
+			attrMap["href"] = st.URLFor(hint.Path)
+		}
+		if hint.As != "" {
+			attrMap["as"] = hint.As
+		}
+		if hint.Crossorigin != "" {
+			attrMap["crossorigin"] = hint.Crossorigin
+		}
+		tags[i] = fmt.Sprintf(`<link %s/>`, mapToAttrs(attrMap, !st.disableEscaping))
+	}
+	return template.HTML(strings.Join(tags, "\n")), nil
+}
+
+// PreconnectTag returns HTML link tag with rel="preconnect" and the crossorigin boolean attribute, e.g. for
+// the origin of a CDN or a font service. Usually not used directly, but registered in template via FuncMap.
+func (st *Static) PreconnectTag(origin string) template.HTML {
+	return template.HTML(fmt.Sprintf(`<link %s crossorigin/>`, st.originHintAttrs("preconnect", origin)))
+}
+
+// DNSPrefetchTag returns HTML link tag with rel="dns-prefetch" for origin. Usually not used directly, but
+// registered in template via FuncMap.
+func (st *Static) DNSPrefetchTag(origin string) template.HTML {
+	return template.HTML(fmt.Sprintf(`<link %s/>`, st.originHintAttrs("dns-prefetch", origin)))
+}
+
+// originHintAttrs returns the rendered attributes of a hint for origin, which isn't resolved through the manifest.
+func (st *Static) originHintAttrs(rel, origin string) string {
+	attrMap := map[string]string{"rel": rel, "href": origin}
+	st.addBuildTimestamp(attrMap)
+	return mapToAttrs(attrMap, !st.disableEscaping)
+}
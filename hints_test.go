@@ -0,0 +1,64 @@
+package asset
+
+import (
+	"github.com/stretchr/testify/require"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+func TestResourceHints(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithResourceHints([]ResourceHint{
+		{Rel: "preconnect", Path: "https://fonts.example.com", Crossorigin: "anonymous"},
+		{Rel: "preload", Path: "js/app.js", As: "script"},
+		{Rel: "prefetch", Path: "css/next.css"},
+	}))
+	require.Nil(t, err)
+	tags, err := static.ResourceHints()
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<link crossorigin="anonymous" href="https://fonts.example.com" rel="preconnect"/>`+"\n"+
+			`<link as="script" href="/static/dist/app-1234.js" rel="preload"/>`+"\n"+
+			`<link href="/static/css/next.css" rel="prefetch"/>`,
+	), tags)
+}
+
+func TestResourceHintsInvalid(t *testing.T) {
+	_, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithResourceHints([]ResourceHint{
+		{Rel: "stylesheet", Path: "css/app.css"},
+	}))
+	require.NotNil(t, err)
+	_, err = NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithResourceHints([]ResourceHint{
+		{Rel: "preload", Path: "js/app.js"},
+	}))
+	require.NotNil(t, err)
+}
+
+func TestResourceHintsEmpty(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil))
+	require.Nil(t, err)
+	tags, err := static.ResourceHints()
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(""), tags)
+}
+
+func TestOriginHintTags(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil))
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<link href="https://cdn.example.com" rel="preconnect" crossorigin/>`),
+		static.PreconnectTag("https://cdn.example.com"))
+	require.Equal(t, template.HTML(`<link href="https://cdn.example.com" rel="dns-prefetch"/>`),
+		static.DNSPrefetchTag("https://cdn.example.com"))
+	require.Equal(t, template.HTML(`<link href="https://cdn.example.com/?a=1&amp;b=&#34;2&#34;" rel="dns-prefetch"/>`),
+		static.DNSPrefetchTag(`https://cdn.example.com/?a=1&b="2"`))
+
+	tmpl, err := static.Template().Parse(
+		`{{ preconnecttag "https://fonts.example.com" }}{{ dnsprefetchtag "https://cdn.example.com" }}`,
+	)
+	require.Nil(t, err)
+	var out strings.Builder
+	require.Nil(t, tmpl.Execute(&out, nil))
+	require.Equal(t, `<link href="https://fonts.example.com" rel="preconnect" crossorigin/>`+
+		`<link href="https://cdn.example.com" rel="dns-prefetch"/>`, out.String())
+}
@@ -0,0 +1,89 @@
+package asset
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// ManifestHandlerOptions configures the handler returned by ManifestHandlerWithOptions.
+type ManifestHandlerOptions struct {
+	// SecurityHeaders are added to every response, e.g. "X-Content-Type-Options": "nosniff".
+	SecurityHeaders map[string]string
+	// RequireAuth, if set, is called for every request; returning false rejects it with 401.
+	RequireAuth func(r *http.Request) bool
+}
+
+// ManifestHandler returns an http.Handler serving the loaded manifest as JSON.
+func (st *Static) ManifestHandler() http.Handler {
+	return st.ManifestHandlerWithOptions(ManifestHandlerOptions{})
+}
+
+// ManifestHandlerWithOptions returns an http.Handler serving the loaded manifest as JSON, configured with opts.
+func (st *Static) ManifestHandlerWithOptions(opts ManifestHandlerOptions) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for name, value := range opts.SecurityHeaders {
+			w.Header().Set(name, value)
+		}
+		if opts.RequireAuth != nil && !opts.RequireAuth(r) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		mapping, ok := st.currentMapping().(*staticMap)
+		if !ok {
+			st.handleError(w, r, errManifestUnavailable)
+			return
+		}
+		content, err := json.Marshal(mapping.innerMap)
+		if err != nil {
+			st.handleError(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(content)
+	})
+}
+
+// HealthCheckHandler returns an http.Handler reporting whether the manifest is loaded, e.g. to be served
+// at /health for load balancers. It responds with 200 OK and {"status":"ok","assets":<number of manifest
+// keys>}, including "cdn" with CDNStatus when WithCDNFailover is used, or with 503 Service Unavailable
+// and {"status":"unavailable"} when there's no mapping.
+func (st *Static) HealthCheckHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		mapping := st.currentMapping()
+		if mapping == nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(healthStatus{Status: "unavailable"})
+			return
+		}
+		json.NewEncoder(w).Encode(healthStatus{Status: "ok", Assets: len(mapping.Keys()), CDN: st.CDNStatus()})
+	})
+}
+
+type healthStatus struct {
+	Status string `json:"status"`
+	Assets int    `json:"assets,omitempty"`
+	CDN    string `json:"cdn,omitempty"`
+}
+
+// WithErrorHandler can be used in NewStatic to provide a function called when the HTTP handlers
+// fail, e.g. to log the error or render a custom error page. By default 500 Internal Server Error
+// is written as plain text.
+func WithErrorHandler(fn func(w http.ResponseWriter, r *http.Request, err error)) optionSetter {
+	return func(st *Static) { st.errorHandler = fn }
+}
+
+func (st *Static) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if st.errorHandler != nil {
+		st.errorHandler(w, r, err)
+		return
+	}
+	defaultErrorHandler(w, r, err)
+}
+
+func defaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+var errManifestUnavailable = errors.New("manifest is not available for this mapping")
@@ -0,0 +1,93 @@
+package asset
+
+import (
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestManifestHandler(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	recorder := httptest.NewRecorder()
+	static.ManifestHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/manifest", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	require.JSONEq(t, `{"js/app.js":"dist/app-1234.js"}`, recorder.Body.String())
+}
+
+func TestHealthCheckHandler(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	recorder := httptest.NewRecorder()
+	static.HealthCheckHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/health", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	require.JSONEq(t, `{"status":"ok","assets":1}`, recorder.Body.String())
+
+	static.SwapManifest(nil)
+	recorder = httptest.NewRecorder()
+	static.HealthCheckHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/health", nil))
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	require.JSONEq(t, `{"status":"unavailable"}`, recorder.Body.String())
+}
+
+func TestManifestHandlerWithOptions(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	handler := static.ManifestHandlerWithOptions(ManifestHandlerOptions{
+		SecurityHeaders: map[string]string{"X-Content-Type-Options": "nosniff", "Cache-Control": "no-store"},
+		RequireAuth:     func(r *http.Request) bool { return r.Header.Get("Authorization") == "secret" },
+	})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/manifest", nil))
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	require.Equal(t, "nosniff", recorder.Header().Get("X-Content-Type-Options"))
+
+	recorder = httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/manifest", nil)
+	request.Header.Set("Authorization", "secret")
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "nosniff", recorder.Header().Get("X-Content-Type-Options"))
+	require.Equal(t, "no-store", recorder.Header().Get("Cache-Control"))
+	require.JSONEq(t, `{}`, recorder.Body.String())
+}
+
+func TestManifestHandlerCustomMapping(t *testing.T) {
+	builder := func() (StaticMapper, error) { return mapperFunc(func(name string) string { return name }), nil }
+	static, err := NewStatic("/static", "manifest.json", WithMappingBuilder(builder))
+	require.Nil(t, err)
+	recorder := httptest.NewRecorder()
+	static.ManifestHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/manifest", nil))
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+	require.Equal(t, "Internal Server Error\n", recorder.Body.String())
+}
+
+func TestManifestHandlerErrorHandler(t *testing.T) {
+	builder := func() (StaticMapper, error) { return mapperFunc(func(name string) string { return name }), nil }
+	var handledErr error
+	errorHandler := func(w http.ResponseWriter, r *http.Request, err error) {
+		handledErr = err
+		w.WriteHeader(http.StatusTeapot)
+	}
+	static, err := NewStatic("/static", "manifest.json", WithMappingBuilder(builder), WithErrorHandler(errorHandler))
+	require.Nil(t, err)
+	recorder := httptest.NewRecorder()
+	static.ManifestHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/manifest", nil))
+	require.Equal(t, http.StatusTeapot, recorder.Code)
+	require.Equal(t, errManifestUnavailable, handledErr)
+}
+
+type mapperFunc func(string) string
+
+func (f mapperFunc) Get(name string) string { return f(name) }
+
+func (f mapperFunc) Has(name string) bool { return f(name) != name }
+
+func (f mapperFunc) Keys() []string { return nil }
@@ -0,0 +1,53 @@
+package asset
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"path"
+)
+
+// InjectIntoHTML inserts tags for assets into an HTML document, right before its last </head> tag
+// (matched case-insensitively). Link tags are generated for .css assets and script tags for .js assets,
+// in the order of assetKeys. Returns an error if </head> isn't found or the type of an asset is unknown.
+func (st *Static) InjectIntoHTML(htmlContent []byte, assetKeys []string) ([]byte, error) {
+	index := lastIndexFold(htmlContent, []byte("</head>"))
+	if index < 0 {
+		return nil, errHeadNotFound
+	}
+	var tags bytes.Buffer
+	for _, key := range assetKeys {
+		var tag template.HTML
+		var err error
+		switch path.Ext(key) {
+		case ".css":
+			tag, err = st.LinkTag(key)
+		case ".js":
+			tag, err = st.ScriptTag(key)
+		default:
+			err = st.assetError(key, fmt.Errorf("can't infer the tag for %s", key))
+		}
+		if err != nil {
+			return nil, err
+		}
+		tags.WriteString(string(tag))
+		tags.WriteString("\n")
+	}
+	injected := make([]byte, 0, len(htmlContent)+tags.Len())
+	injected = append(injected, htmlContent[:index]...)
+	injected = append(injected, tags.Bytes()...)
+	return append(injected, htmlContent[index:]...), nil
+}
+
+// lastIndexFold returns the index of the last case-insensitive occurrence of sep in s, or -1.
+func lastIndexFold(s, sep []byte) int {
+	for i := len(s) - len(sep); i >= 0; i-- {
+		if bytes.EqualFold(s[i:i+len(sep)], sep) {
+			return i
+		}
+	}
+	return -1
+}
+
+var errHeadNotFound = errors.New("</head> not found in HTML")
@@ -0,0 +1,29 @@
+package asset
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestInjectIntoHTML(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	content, err := static.InjectIntoHTML(
+		[]byte("<html><HEAD><title>t</title></HEAD><body></body></html>"), []string{"css/style.css", "js/app.js"},
+	)
+	require.Nil(t, err)
+	require.Equal(t, "<html><HEAD><title>t</title>"+
+		`<link href="/static/css/style.css" rel="stylesheet" type="text/css"/>`+"\n"+
+		`<script src="/static/dist/app-1234.js" type="text/javascript"></script>`+"\n"+
+		"</HEAD><body></body></html>", string(content))
+}
+
+func TestInjectIntoHTMLErrors(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil))
+	require.Nil(t, err)
+	_, err = static.InjectIntoHTML([]byte("<html><body></body></html>"), []string{"js/app.js"})
+	require.Equal(t, errHeadNotFound, err)
+	_, err = static.InjectIntoHTML([]byte("<head></head>"), []string{"img/logo.png"})
+	require.NotNil(t, err)
+}
@@ -0,0 +1,88 @@
+package asset
+
+import (
+	"fmt"
+	"html/template"
+	"io/fs"
+	"regexp"
+)
+
+var (
+	closingScriptRegexp = regexp.MustCompile(`(?i)</script`)
+	closingStyleRegexp  = regexp.MustCompile(`(?i)</style`)
+)
+
+// InlineScriptTag returns HTML script tag with the content of the asset file inlined. The file is read from
+// the file system set with WithFS or, without it, with the manifest loader, e.g. Asset of go-bindata given
+// to WithManifestLoader. Sequences closing the tag in the content are escaped. Inline scripts can't have
+// the integrity attribute, so WithUseSri doesn't apply. See ScriptTag for additional information.
+// Usually not used directly, but registered in template via FuncMap.
+func (st *Static) InlineScriptTag(path string, attrs ...string) (template.HTML, error) {
+	defer st.profile(path)()
+	defaultAttrMap := map[string]string{"type": "text/javascript"}
+	st.addBuildTimestamp(defaultAttrMap)
+	st.addNonce(defaultAttrMap)
+	attrMap, err := attrSliceToMap(attrs)
+	if err != nil {
+		return "", st.assetError(path, err)
+	}
+	content, err := st.inlineContent(path)
+	if err != nil {
+		return "", st.assetError(path, err)
+	}
+	updateMap(defaultAttrMap, attrMap)
+	st.recordNonce(defaultAttrMap["nonce"])
+	body := closingScriptRegexp.ReplaceAllString(content, `<\/script`)
+	tag := fmt.Sprintf(`<script %s>%s</script>`, mapToAttrs(defaultAttrMap, !st.disableEscaping), body)
+	if !st.firstRender("inlinescript", path) {
+		return "", nil
+	}
+	return st.annotate(path, tag), nil
+}
+
+// InlineLinkTag returns HTML style tag with the content of the stylesheet file inlined instead of a link
+// tag referencing it. See InlineScriptTag for additional information. Usually not used directly, but
+// registered in template via FuncMap.
+func (st *Static) InlineLinkTag(path string, attrs ...string) (template.HTML, error) {
+	defer st.profile(path)()
+	defaultAttrMap := map[string]string{"type": "text/css"}
+	st.addBuildTimestamp(defaultAttrMap)
+	st.addNonce(defaultAttrMap)
+	attrMap, err := attrSliceToMap(attrs)
+	if err != nil {
+		return "", st.assetError(path, err)
+	}
+	content, err := st.inlineContent(path)
+	if err != nil {
+		return "", st.assetError(path, err)
+	}
+	updateMap(defaultAttrMap, attrMap)
+	st.recordNonce(defaultAttrMap["nonce"])
+	body := closingStyleRegexp.ReplaceAllString(content, `<\/style`)
+	tag := fmt.Sprintf(`<style %s>%s</style>`, mapToAttrs(defaultAttrMap, !st.disableEscaping), body)
+	if !st.firstRender("inlinestyle", path) {
+		return "", nil
+	}
+	return st.annotate(path, tag), nil
+}
+
+// inlineContent returns the content of the file an asset is resolved to.
+func (st *Static) inlineContent(path string) (string, error) {
+	resolved, err := st.resolveTag(path)
+	if err != nil {
+		return "", err
+	}
+	content, err := st.readAsset(stripQuery(resolved))
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// readAsset reads the asset file from the file system set with WithFS or, without it, with the manifest loader.
+func (st *Static) readAsset(name string) ([]byte, error) {
+	if st.fsys == nil && st.manifestLoader != nil {
+		return st.manifestLoader(name)
+	}
+	return fs.ReadFile(st.assetFS(), name)
+}
@@ -0,0 +1,75 @@
+package asset
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"html/template"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestInlineScriptTag(t *testing.T) {
+	fsys := fstest.MapFS{"dist/app-1234.js": {Data: []byte(`if (a < b && c) { document.write("</SCRIPT>") }`)}}
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithFS(fsys), WithUseSri(true))
+	require.Nil(t, err)
+	tmpl, err := static.Template().Parse(`{{ inlinescripttag "js/app.js" "nonce" "abc" }}`)
+	require.Nil(t, err)
+	var out strings.Builder
+	require.Nil(t, tmpl.Execute(&out, nil))
+	require.Equal(t,
+		`<script nonce="abc" type="text/javascript">if (a < b && c) { document.write("<\/script>") }</script>`,
+		out.String(),
+	)
+}
+
+func TestInlineScriptTagErrors(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithFS(fstest.MapFS{}))
+	require.Nil(t, err)
+	_, err = static.InlineScriptTag("js/missing.js")
+	require.True(t, errors.Is(err, fs.ErrNotExist))
+	tag, err := static.InlineScriptTag("js/app.js", "defer")
+	require.Equal(t, template.HTML(""), tag)
+	require.NotNil(t, err)
+}
+
+func TestInlineScriptTagFromLoader(t *testing.T) {
+	files := map[string]string{"manifest.json": `{"js/app.js":"dist/app-1234.js"}`, "dist/app-1234.js": "alert(1)"}
+	loader := func(name string) ([]byte, error) {
+		if content, ok := files[name]; ok {
+			return []byte(content), nil
+		}
+		return nil, fs.ErrNotExist
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	tag, err := static.InlineScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<script type="text/javascript">alert(1)</script>`), tag)
+
+	static, err = NewStatic("/static", "manifest.json", WithManifestFromReader(strings.NewReader(files["manifest.json"])))
+	require.Nil(t, err)
+	_, err = static.InlineScriptTag("js/app.js")
+	require.True(t, errors.Is(err, fs.ErrNotExist))
+}
+
+func TestInlineLinkTag(t *testing.T) {
+	css := `body > p { font-family: "Open Sans"; background: url('a.png?x=1&y=2') }`
+	fsys := fstest.MapFS{"dist/style-1234.css": {Data: []byte(css)}}
+	loader := func(name string) ([]byte, error) { return []byte(`{"css/style.css":"dist/style-1234.css"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithFS(fsys))
+	require.Nil(t, err)
+	tmpl, err := static.Template().Parse(`{{ inlinelinktag "css/style.css" "media" "print" }}`)
+	require.Nil(t, err)
+	var out strings.Builder
+	require.Nil(t, tmpl.Execute(&out, nil))
+	require.Equal(t, `<style media="print" type="text/css">`+css+`</style>`, out.String())
+	require.NotContains(t, out.String(), "href")
+
+	_, err = static.InlineLinkTag("css/missing.css")
+	require.True(t, errors.Is(err, fs.ErrNotExist))
+	_, err = static.InlineLinkTag("css/style.css", "media")
+	require.NotNil(t, err)
+}
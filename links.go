@@ -0,0 +1,46 @@
+package asset
+
+import (
+	"html/template"
+	"path/filepath"
+	"strings"
+)
+
+// LinkManifestTag returns HTML link tag with rel="manifest" for the web app manifest of Progressive Web Apps,
+// e.g. "site.webmanifest", which can be fingerprinted like other assets. Usually not used directly, but
+// registered in template via FuncMap.
+func (st *Static) LinkManifestTag(path string) (template.HTML, error) {
+	defer st.profile(path)()
+	return st.relLinkTag(path, map[string]string{"rel": "manifest"}, nil)
+}
+
+// AppleTouchIconTag returns HTML link tag with rel="apple-touch-icon" for iOS web clips; size, e.g. "180",
+// is rendered as sizes="180x180" unless empty. See ScriptTag for additional information. Usually not used
+// directly, but registered in template via FuncMap.
+func (st *Static) AppleTouchIconTag(path string, size string, attrs ...string) (template.HTML, error) {
+	defer st.profile(path)()
+	defaultAttrMap := map[string]string{"rel": "apple-touch-icon"}
+	if size != "" {
+		defaultAttrMap["sizes"] = size + "x" + size
+	}
+	return st.relLinkTag(path, defaultAttrMap, attrs)
+}
+
+// faviconMimeTypes maps icon extensions to the MIME types used in the type attribute of favicon tags.
+var faviconMimeTypes = map[string]string{
+	".ico": "image/x-icon",
+	".png": "image/png",
+	".svg": "image/svg+xml",
+}
+
+// FaviconTag returns HTML link tag with rel="icon", with the type attribute derived from the extension of
+// path for .ico, .png and .svg icons. See ScriptTag for additional information. Usually not used directly,
+// but registered in template via FuncMap.
+func (st *Static) FaviconTag(path string, attrs ...string) (template.HTML, error) {
+	defer st.profile(path)()
+	defaultAttrMap := map[string]string{"rel": "icon"}
+	if mimeType, ok := faviconMimeTypes[strings.ToLower(filepath.Ext(path))]; ok {
+		defaultAttrMap["type"] = mimeType
+	}
+	return st.relLinkTag(path, defaultAttrMap, attrs)
+}
@@ -0,0 +1,59 @@
+package asset
+
+import (
+	"github.com/stretchr/testify/require"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+func TestLinkManifestTag(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"site.webmanifest":"site-1234.webmanifest"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	tag, err := static.LinkManifestTag("site.webmanifest")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<link href="/static/site-1234.webmanifest" rel="manifest"/>`), tag)
+
+	tmpl, err := static.Template().Parse(`{{ linkmanifesttag "app.webmanifest" }}`)
+	require.Nil(t, err)
+	var out strings.Builder
+	require.Nil(t, tmpl.Execute(&out, nil))
+	require.Equal(t, `<link href="/static/app.webmanifest" rel="manifest"/>`, out.String())
+}
+
+func TestAppleTouchIconTag(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"img/icon.png":"img/icon-1234.png"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	tag, err := static.AppleTouchIconTag("img/icon.png", "180")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<link href="/static/img/icon-1234.png" rel="apple-touch-icon" sizes="180x180"/>`), tag)
+	tag, err = static.AppleTouchIconTag("img/other.png", "")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<link href="/static/img/other.png" rel="apple-touch-icon"/>`), tag)
+	tag, err = static.AppleTouchIconTag("img/icon.png", "152", "rel", "apple-touch-icon-precomposed")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<link href="/static/img/icon-1234.png" rel="apple-touch-icon-precomposed" sizes="152x152"/>`,
+	), tag)
+}
+
+func TestFaviconTag(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"favicon.ico":"favicon-1234.ico"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	for path, expected := range map[string]string{
+		"favicon.ico":  `<link href="/static/favicon-1234.ico" rel="icon" type="image/x-icon"/>`,
+		"img/icon.PNG": `<link href="/static/img/icon.PNG" rel="icon" type="image/png"/>`,
+		"img/icon.svg": `<link href="/static/img/icon.svg" rel="icon" type="image/svg+xml"/>`,
+		"img/icon.gif": `<link href="/static/img/icon.gif" rel="icon"/>`,
+	} {
+		tag, err := static.FaviconTag(path)
+		require.Nil(t, err)
+		require.Equal(t, template.HTML(expected), tag)
+	}
+	tag, err := static.FaviconTag("img/icon.png", "sizes", "32x32", "type", "image/webp")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<link href="/static/img/icon.png" rel="icon" sizes="32x32" type="image/webp"/>`), tag)
+}
@@ -0,0 +1,56 @@
+package asset
+
+import (
+	"errors"
+	"sync"
+)
+
+// readLocker is the read locking part of sync.RWMutex.
+type readLocker interface {
+	RLock()
+	RUnlock()
+}
+
+// WithManifestLock can be used in NewStatic to provide the lock guarding replacement of the manifest instead
+// of the internal sync.RWMutex. Unless WithManifestRLock is used as well, mu is also locked for reading.
+func WithManifestLock(mu sync.Locker) optionSetter {
+	return func(st *Static) { st.mappingLock = mu }
+}
+
+// WithManifestRLock can be used together with WithManifestLock to provide the lock used when the manifest
+// is read. If mu implements sync.Locker, WithManifestLock is optional.
+func WithManifestRLock(mu interface {
+	RLock()
+	RUnlock()
+}) optionSetter {
+	return func(st *Static) { st.mappingRLock = mu }
+}
+
+// initLocks sets the locks guarding the mapping, defaulting to sync.RWMutex.
+func (st *Static) initLocks() {
+	if st.mappingLock == nil {
+		if locker, ok := st.mappingRLock.(sync.Locker); ok {
+			st.mappingLock = locker
+		} else if st.mappingRLock != nil {
+			st.setOptionErr(errNoManifestLock)
+			return
+		}
+	}
+	if st.mappingLock == nil {
+		mu := &sync.RWMutex{}
+		st.mappingLock, st.mappingRLock = mu, mu
+	}
+	if st.mappingRLock == nil {
+		st.mappingRLock = lockerReader{st.mappingLock}
+	}
+}
+
+// lockerReader uses a sync.Locker for read locking.
+type lockerReader struct {
+	sync.Locker
+}
+
+func (l lockerReader) RLock()   { l.Lock() }
+func (l lockerReader) RUnlock() { l.Unlock() }
+
+var errNoManifestLock = errors.New("WithManifestRLock requires WithManifestLock")
@@ -0,0 +1,60 @@
+package asset
+
+import (
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+)
+
+type countingLock struct {
+	sync.Mutex
+	locks int
+}
+
+func (l *countingLock) Lock() {
+	l.Mutex.Lock()
+	l.locks++
+}
+
+type countingRLock struct {
+	mu     sync.Mutex
+	rlocks int
+}
+
+func (l *countingRLock) RLock() {
+	l.mu.Lock()
+	l.rlocks++
+}
+
+func (l *countingRLock) RUnlock() { l.mu.Unlock() }
+
+func TestManifestLock(t *testing.T) {
+	lock := &countingLock{}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithManifestLock(lock))
+	require.Nil(t, err)
+	static.URLFor("js/app.js")
+	require.Equal(t, 1, lock.locks)
+	static.SwapManifest(mapperFunc(func(name string) string { return name }))
+	require.Equal(t, 2, lock.locks)
+}
+
+func TestManifestRLock(t *testing.T) {
+	lock := &countingLock{}
+	rlock := &countingRLock{}
+	static, err := NewStatic(
+		"/static", "manifest.json", WithManifestLoader(nil), WithManifestLock(lock), WithManifestRLock(rlock),
+	)
+	require.Nil(t, err)
+	static.URLFor("js/app.js")
+	static.SwapManifest(mapperFunc(func(name string) string { return name }))
+	require.Equal(t, 1, lock.locks)
+	require.Equal(t, 1, rlock.rlocks)
+}
+
+func TestManifestRLockWithoutLock(t *testing.T) {
+	_, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithManifestRLock(&countingRLock{}))
+	require.Equal(t, errNoManifestLock, err)
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithManifestRLock(&sync.RWMutex{}))
+	require.Nil(t, err)
+	require.Equal(t, static.mappingRLock, static.mappingLock)
+}
@@ -0,0 +1,357 @@
+package asset
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var defaultInferredExtensions = []string{".js", ".css", ".ts", ".scss"}
+
+// ManifestChecksum returns the hex encoded SHA-256 hash of the loaded manifest. Entries are hashed sorted by
+// key, so the checksum changes only when the manifest contents change. Can be used e.g. as a part of ETags.
+func (st *Static) ManifestChecksum() (string, error) {
+	mapping, ok := st.currentMapping().(*staticMap)
+	if !ok {
+		return "", errManifestUnavailable
+	}
+	// json.Marshal sorts map keys
+	content, err := json.Marshal(mapping.innerMap)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// manifestFiles returns the sorted, unique asset files referenced in the manifest values.
+func (st *Static) manifestFiles() ([]string, error) {
+	mapping, ok := st.currentMapping().(*staticMap)
+	if !ok {
+		return nil, errManifestUnavailable
+	}
+	files := []string{}
+	seen := map[string]bool{}
+	for _, value := range mapping.innerMap {
+		if file := stripQuery(value); !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// WithManifestCompression can be used in NewStatic to load compressed manifests: decompress is applied to
+// the manifest contents, both loaded and pushed, before they're parsed. Compression formats are provided by
+// sub-packages, e.g. zstd, to keep the core package free of the dependencies.
+func WithManifestCompression(decompress func([]byte) ([]byte, error)) optionSetter {
+	return func(st *Static) { st.decompress = decompress }
+}
+
+// decompressingLoader returns a Loader decompressing the contents returned by load.
+func decompressingLoader(load Loader, decompress func([]byte) ([]byte, error)) Loader {
+	return func(path string) ([]byte, error) {
+		content, err := load(path)
+		if err != nil {
+			return nil, err
+		}
+		return decompress(content)
+	}
+}
+
+// WithManifestValidator can be used in NewStatic to check loaded and pushed manifests with validate, which
+// receives the manifest parsed from JSON before any changes. An error returned by validate is returned by
+// NewStatic, or by ReloadManifest when reloading, and the manifest isn't used. Can be used multiple times.
+func WithManifestValidator(validate func(manifest interface{}) error) optionSetter {
+	return func(st *Static) { st.validators = append(st.validators, validate) }
+}
+
+// validatingLoader returns a Loader checking the contents returned by load with the validators. Contents that
+// aren't valid JSON are returned as they are, to be reported when the manifest is parsed.
+func validatingLoader(load Loader, validators []func(manifest interface{}) error) Loader {
+	return func(path string) ([]byte, error) {
+		content, err := load(path)
+		if err != nil {
+			return nil, err
+		}
+		var manifest interface{}
+		if json.Unmarshal(content, &manifest) != nil {
+			return content, nil
+		}
+		for _, validate := range validators {
+			if err := validate(manifest); err != nil {
+				return nil, err
+			}
+		}
+		return content, nil
+	}
+}
+
+// manifestTransform is applied to the parsed manifest before the mapping is created.
+type manifestTransform func(map[string]string) map[string]string
+
+// WithManifestKeyRegexp can be used in NewStatic to keep only the manifest entries with keys matching
+// pattern. Entries are filtered once, when the manifest is loaded. An invalid pattern makes NewStatic
+// return an error.
+func WithManifestKeyRegexp(pattern string) optionSetter {
+	return func(st *Static) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			st.setOptionErr(err)
+			return
+		}
+		st.transforms = append(st.transforms, func(manifest map[string]string) map[string]string {
+			return filterManifest(manifest, func(key, _ string) bool { return re.MatchString(key) })
+		})
+	}
+}
+
+// WithManifestValueRegexp can be used in NewStatic to keep only the manifest entries with values
+// matching pattern. Combined with WithManifestKeyRegexp, both the key and the value must match.
+// An invalid pattern makes NewStatic return an error.
+func WithManifestValueRegexp(pattern string) optionSetter {
+	return func(st *Static) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			st.setOptionErr(err)
+			return
+		}
+		st.transforms = append(st.transforms, func(manifest map[string]string) map[string]string {
+			return filterManifest(manifest, func(_, value string) bool { return re.MatchString(value) })
+		})
+	}
+}
+
+// WithManifestPrefix can be used in NewStatic to strip prefix from all manifest keys, e.g. when the manifest
+// generator includes the public URL path in keys ("/public/js/app.js" instead of "js/app.js"). Keys without
+// the prefix are left as they are. If prefix starts with "+", the rest of it is prepended to all keys instead.
+func WithManifestPrefix(prefix string) optionSetter {
+	return func(st *Static) {
+		transformKey := func(key string) string { return strings.TrimPrefix(key, prefix) }
+		if strings.HasPrefix(prefix, "+") {
+			transformKey = func(key string) string { return prefix[1:] + key }
+		}
+		st.transforms = append(st.transforms, func(manifest map[string]string) map[string]string {
+			return transformManifestKeys(manifest, transformKey)
+		})
+	}
+}
+
+// WithManifestPatchFunc can be used in NewStatic to modify the manifest programmatically after it's loaded,
+// e.g. to add entries computed at runtime. patch receives the entries of the manifest and returns the entries
+// to be used.
+func WithManifestPatchFunc(patch func(m map[string]string) map[string]string) optionSetter {
+	return func(st *Static) { st.transforms = append(st.transforms, patch) }
+}
+
+// WithManifestTransform can be used in NewStatic to change the manifest before it's used, e.g. to strip
+// prefixes from keys, lowercase them or drop entries other than scripts and stylesheets. transform receives
+// a copy of the entries, after the other options changing the manifest used before it, and returns the
+// entries to be used. Can be used multiple times; transforms are applied in order.
+func WithManifestTransform(transform func(map[string]string) map[string]string) optionSetter {
+	return func(st *Static) {
+		st.transforms = append(st.transforms, func(manifest map[string]string) map[string]string {
+			entries := make(map[string]string, len(manifest))
+			for key, value := range manifest {
+				entries[key] = value
+			}
+			return transform(entries)
+		})
+	}
+}
+
+// WithWebpackManifest can be used in NewStatic to load manifests produced by WebpackManifestPlugin v5+,
+// which may have the public path baked into values. Values that are absolute http(s) URLs are then used
+// as they are, without the URL prefix; other values are treated as usual.
+func WithWebpackManifest() optionSetter {
+	return func(st *Static) { st.webpackManifest = true }
+}
+
+// Manifest formats supported by WithManifestFormat.
+const (
+	// ManifestFormatRev is the format of gulp-rev and similar tools, mapping asset paths to file names.
+	ManifestFormatRev = "rev"
+	// ManifestFormatVite is the format of Vite, mapping source paths to chunk objects.
+	ManifestFormatVite = "vite"
+	// ManifestFormatWebpackStats is the stats.json of webpack, see WebpackStatsMapper.
+	ManifestFormatWebpackStats = "webpack-stats"
+	// ManifestFormatYAML is a flat YAML mapping of asset paths to file names. It requires a YAML parser set
+	// with WithManifestParser, e.g. by the yaml package, so that the core package doesn't depend on one.
+	ManifestFormatYAML = "yaml"
+)
+
+var errNoYAMLParser = errors.New("yaml manifest format requires a manifest parser")
+
+// ManifestParser parses the manifest contents into the mapping of asset paths to file names.
+type ManifestParser interface {
+	Parse(content []byte) (map[string]string, error)
+}
+
+// JSONManifestParser is the default ManifestParser, parsing manifests as JSON objects. Entries with values
+// other than strings are dropped.
+type JSONManifestParser struct{}
+
+// Parse implements ManifestParser.
+func (JSONManifestParser) Parse(content []byte) (map[string]string, error) {
+	manifest, err := parseJSONObject(content)
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[string]string, len(manifest))
+	for key, value := range manifest {
+		if str, ok := value.(string); ok {
+			entries[key] = str
+		}
+	}
+	return entries, nil
+}
+
+func parseJSONObject(content []byte) (map[string]interface{}, error) {
+	var manifest interface{}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, err
+	}
+	object, ok := manifest.(map[string]interface{})
+	if !ok {
+		return nil, errManifestNotObject
+	}
+	return object, nil
+}
+
+// WithManifestParser can be used in NewStatic to parse manifests with parser instead of as JSON, e.g. with
+// ManifestFormatYAML. Errors returned by parser match ErrInvalidManifest. ManifestFormatVite,
+// ManifestFormatWebpackStats and WithManifestValidator, which are specific to JSON, don't use it.
+func WithManifestParser(parser ManifestParser) optionSetter {
+	return func(st *Static) { st.manifestParser = parser }
+}
+
+// WithManifestFormat can be used in NewStatic to load manifests in another format than the default
+// ManifestFormatRev. With ManifestFormatVite assets are resolved to the file field of the chunks, and the
+// stylesheets imported by the chunks are returned by LinkedAssets. With ManifestFormatWebpackStats the
+// mapping is WebpackStatsMapper, to which the options modifying the manifest don't apply. ManifestFormatYAML
+// requires WithManifestParser. An unsupported format makes NewStatic return an error.
+func WithManifestFormat(format string) optionSetter {
+	return func(st *Static) {
+		switch format {
+		case ManifestFormatRev, ManifestFormatVite, ManifestFormatWebpackStats, ManifestFormatYAML:
+			st.manifestFormat = format
+		default:
+			st.setOptionErr(fmt.Errorf("unsupported manifest format %q", format))
+		}
+	}
+}
+
+// LinkedAssets returns the files linked to an asset in the manifest, e.g. the stylesheets imported by a chunk
+// with ManifestFormatVite. The files are paths relative to the URL prefix, like the resolved assets.
+func (st *Static) LinkedAssets(path string) []string {
+	mapping, ok := st.currentMapping().(*staticMap)
+	if !ok {
+		return nil
+	}
+	return mapping.linked[path]
+}
+
+// viteParser is the ManifestParser of ManifestFormatVite, replacing Vite chunks with their files, and storing
+// the stylesheets of the chunks in linked.
+type viteParser struct {
+	linked map[string][]string
+}
+
+func (p viteParser) Parse(content []byte) (map[string]string, error) {
+	manifest, err := parseJSONObject(content)
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[string]string, len(manifest))
+	for key, value := range manifest {
+		if str, ok := value.(string); ok {
+			entries[key] = str
+			continue
+		}
+		chunk, _ := value.(map[string]interface{})
+		if file, ok := chunk["file"].(string); ok {
+			entries[key] = file
+		}
+		css, _ := chunk["css"].([]interface{})
+		for _, item := range css {
+			if file, ok := item.(string); ok {
+				p.linked[key] = append(p.linked[key], file)
+			}
+		}
+	}
+	return entries, nil
+}
+
+// WithKeyNormalizer can be used in NewStatic to normalize the manifest keys and the looked up paths with
+// normalize, e.g. strings.ToLower for case-insensitive lookups when the casing differs between the manifest
+// and templates. Keys are normalized when the manifest is loaded, so Keys returns the normalized ones.
+func WithKeyNormalizer(normalize func(string) string) optionSetter {
+	return func(st *Static) { st.keyNormalizer = normalize }
+}
+
+// WithPathPrefix can be used in NewStatic to prepend prefix to the looked up paths, e.g. "src/" when the
+// manifest keys are relative to the project root, but templates reference assets relative to src. Unlike
+// WithManifestPrefix, the manifest keys are left as they are.
+func WithPathPrefix(prefix string) optionSetter {
+	return func(st *Static) {
+		st.pathRewrites = append(st.pathRewrites, func(path string) string { return prefix + path })
+	}
+}
+
+// WithPathStrip can be used in NewStatic to strip prefix from the looked up paths, e.g. "public/" when
+// templates reference assets with the public directory, but the manifest keys are relative to it. Paths
+// without the prefix are left as they are.
+func WithPathStrip(prefix string) optionSetter {
+	return func(st *Static) {
+		st.pathRewrites = append(st.pathRewrites, func(path string) string { return strings.TrimPrefix(path, prefix) })
+	}
+}
+
+// rewritePath applies the changes of WithPathPrefix and WithPathStrip to path, in the order they were used.
+func (st *Static) rewritePath(path string) string {
+	for _, rewrite := range st.pathRewrites {
+		path = rewrite(path)
+	}
+	return path
+}
+
+// WithManifestKeyStripExtension can be used in NewStatic to allow referencing assets without extensions,
+// e.g. {{ scripttag "js/app" }}. Names not found in the manifest are looked up again with every inferred
+// extension appended in turn (".js", ".css", ".ts" and ".scss" unless changed with WithInferredExtensions).
+func WithManifestKeyStripExtension() optionSetter {
+	return func(st *Static) { st.stripExtension = true }
+}
+
+// WithInferredExtensions can be used together with WithManifestKeyStripExtension to change the extensions
+// tried, in order, for names not found in the manifest.
+func WithInferredExtensions(exts []string) optionSetter {
+	return func(st *Static) { st.inferredExts = exts }
+}
+
+func isAbsoluteURL(value string) bool {
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+}
+
+func transformManifestKeys(manifest map[string]string, transform func(string) string) map[string]string {
+	transformed := make(map[string]string, len(manifest))
+	for key, value := range manifest {
+		transformed[transform(key)] = value
+	}
+	return transformed
+}
+
+func filterManifest(manifest map[string]string, keep func(key, value string) bool) map[string]string {
+	filtered := make(map[string]string, len(manifest))
+	for key, value := range manifest {
+		if keep(key, value) {
+			filtered[key] = value
+		}
+	}
+	return filtered
+}
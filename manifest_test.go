@@ -0,0 +1,351 @@
+package asset
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"html/template"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestManifestKeyRegexp(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"admin/app.js":"dist/admin-1234.js", "js/app.js":"dist/app-1234.js"}`), nil
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithManifestKeyRegexp("^admin/"))
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/admin-1234.js", static.URLFor("admin/app.js"))
+	require.Equal(t, "/static/js/app.js", static.URLFor("js/app.js"))
+}
+
+func TestManifestKeyRegexpInvalid(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{}`), nil }
+	_, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithManifestKeyRegexp("("))
+	require.NotNil(t, err)
+}
+
+func TestManifestValueRegexp(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"cdn/app-1234.js", "js/other.js":"dist/other-1234.js", "js/broken.js": 1}`), nil
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithManifestValueRegexp("^cdn/"))
+	require.Nil(t, err)
+	require.Equal(t, "/static/cdn/app-1234.js", static.URLFor("js/app.js"))
+	require.Equal(t, "/static/js/other.js", static.URLFor("js/other.js"))
+	require.Equal(t, "/static/js/broken.js", static.URLFor("js/broken.js"))
+}
+
+func TestManifestKeyAndValueRegexp(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"admin/app.js":"cdn/admin-1234.js", "admin/other.js":"dist/other-1234.js", "js/app.js":"cdn/app-1234.js"}`), nil
+	}
+	static, err := NewStatic(
+		"/static", "manifest.json", WithManifestLoader(loader),
+		WithManifestKeyRegexp("^admin/"), WithManifestValueRegexp("^cdn/"),
+	)
+	require.Nil(t, err)
+	require.Equal(t, "/static/cdn/admin-1234.js", static.URLFor("admin/app.js"))
+	require.Equal(t, "/static/admin/other.js", static.URLFor("admin/other.js"))
+	require.Equal(t, "/static/js/app.js", static.URLFor("js/app.js"))
+}
+
+func TestManifestValueRegexpInvalid(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{}`), nil }
+	_, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithManifestValueRegexp("["))
+	require.NotNil(t, err)
+}
+
+func TestManifestPrefix(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"/public/js/app.js":"dist/app-1234.js", "js/other.js":"dist/other-1234.js"}`), nil
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithManifestPrefix("/public/"))
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+	require.Equal(t, "/static/dist/other-1234.js", static.URLFor("js/other.js"))
+}
+
+func TestManifestPrefixPrepend(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithManifestPrefix("+admin/"))
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("admin/js/app.js"))
+	require.Equal(t, "/static/js/app.js", static.URLFor("js/app.js"))
+}
+
+func TestWebpackManifest(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"https://cdn.example.com/js/app-1234.js", "js/other.js":"js/other-1234.js"}`), nil
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithWebpackManifest())
+	require.Nil(t, err)
+	require.Equal(t, "https://cdn.example.com/js/app-1234.js", static.URLFor("js/app.js"))
+	require.Equal(t, "/static/js/other-1234.js", static.URLFor("js/other.js"))
+	static, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	require.Equal(t, "/static/https://cdn.example.com/js/app-1234.js", static.URLFor("js/app.js"))
+}
+
+func TestManifestKeyStripExtension(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app":"dist/app", "js/app.js":"dist/app-1234.js", "css/style.css":"dist/style-1234.css", "js/main.min.js":"dist/main-1234.min.js"}`), nil
+	}
+	static, err := NewStatic(
+		"/static", "manifest.json", WithManifestLoader(loader), WithUseMinified(true), WithManifestKeyStripExtension(),
+	)
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app", static.URLFor("js/app"))
+	require.Equal(t, "/static/dist/style-1234.css", static.URLFor("css/style"))
+	require.Equal(t, "/static/dist/main-1234.min.js", static.URLFor("js/main"))
+	require.Equal(t, "/static/img/logo", static.URLFor("img/logo"))
+}
+
+func TestInferredExtensions(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"dist/app-1234.js", "js/app.mjs":"dist/app-1234.mjs"}`), nil
+	}
+	static, err := NewStatic(
+		"/static", "manifest.json", WithManifestLoader(loader),
+		WithManifestKeyStripExtension(), WithInferredExtensions([]string{".mjs", ".js"}),
+	)
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app-1234.mjs", static.URLFor("js/app"))
+	static, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	require.Equal(t, "/static/js/app", static.URLFor("js/app"))
+}
+
+func TestManifestChecksum(t *testing.T) {
+	manifest := `{"js/app.js":"dist/app-1234.js", "css/style.css":"dist/style-1234.css"}`
+	loader := func(name string) ([]byte, error) { return []byte(manifest), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	checksum, err := static.ManifestChecksum()
+	require.Nil(t, err)
+	require.Len(t, checksum, 64)
+
+	manifest = `{"css/style.css":"dist/style-1234.css", "js/app.js":"dist/app-1234.js"}`
+	static, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	reordered, err := static.ManifestChecksum()
+	require.Nil(t, err)
+	require.Equal(t, checksum, reordered)
+
+	manifest = `{"css/style.css":"dist/style-5678.css", "js/app.js":"dist/app-1234.js"}`
+	static, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	changed, err := static.ManifestChecksum()
+	require.Nil(t, err)
+	require.NotEqual(t, checksum, changed)
+}
+
+func TestManifestChecksumCustomMapping(t *testing.T) {
+	builder := func() (StaticMapper, error) { return mapperFunc(func(name string) string { return name }), nil }
+	static, err := NewStatic("/static", "manifest.json", WithMappingBuilder(builder))
+	require.Nil(t, err)
+	_, err = static.ManifestChecksum()
+	require.Equal(t, errManifestUnavailable, err)
+}
+
+func TestManifestPatchFunc(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"dist/app-1234.js", "js/old.js":"dist/old-1234.js", "js/broken.js": 1}`), nil
+	}
+	patch := func(m map[string]string) map[string]string {
+		require.Equal(t, map[string]string{"js/app.js": "dist/app-1234.js", "js/old.js": "dist/old-1234.js"}, m)
+		delete(m, "js/old.js")
+		m["build-info.json"] = "build-info-abcd.json"
+		return m
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithManifestPatchFunc(patch))
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+	require.Equal(t, "/static/js/old.js", static.URLFor("js/old.js"))
+	require.Equal(t, "/static/build-info-abcd.json", static.URLFor("build-info.json"))
+}
+
+func TestManifestTransform(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"app-1234.js", "css/style.css":"style-1234.css", "img/logo.png":"logo-1234.png"}`), nil
+	}
+	prefix := func(m map[string]string) map[string]string {
+		for key, value := range m {
+			m[key] = "dist/" + value
+		}
+		return m
+	}
+	scriptsOnly := func(m map[string]string) map[string]string {
+		for key := range m {
+			if !strings.HasSuffix(key, ".js") && !strings.HasSuffix(key, ".css") {
+				delete(m, key)
+			}
+		}
+		return m
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader),
+		WithManifestTransform(prefix), WithManifestTransform(scriptsOnly))
+	require.Nil(t, err)
+	tag, err := static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<script src="/static/dist/app-1234.js" type="text/javascript"></script>`), tag)
+	tag, err = static.LinkTag("css/style.css")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<link href="/static/dist/style-1234.css" rel="stylesheet" type="text/css"/>`), tag)
+	require.Equal(t, "/static/img/logo.png", static.URLFor("img/logo.png"))
+}
+
+func TestKeyNormalizer(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"dist/App-1234.js", "CSS/Style.css":"dist/Style-1234.css"}`), nil
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithKeyNormalizer(strings.ToLower))
+	require.Nil(t, err)
+	require.Equal(t, "dist/App-1234.js", static.currentMapping().Get("JS/App.JS"))
+	require.Equal(t, "/static/dist/App-1234.js", static.URLFor("JS/App.JS"))
+	require.Equal(t, "/static/dist/Style-1234.css", static.URLFor("css/style.css"))
+	require.Equal(t, "/static/Other.js", static.URLFor("Other.js"))
+	require.True(t, static.Has("Css/Style.CSS"))
+	require.False(t, static.Has("js/other.js"))
+	require.Equal(t, []string{"css/style.css", "js/app.js"}, static.Keys())
+}
+
+func TestPathPrefix(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"src/app.js":"dist/app-1234.js", "src/admin/app.js":"dist/admin-1234.js"}`), nil
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithPathPrefix("src/"))
+	require.Nil(t, err)
+	require.Equal(t, "dist/app-1234.js", static.currentMapping().Get("app.js"))
+	require.Equal(t, "/static/dist/admin-1234.js", static.URLFor("admin/app.js"))
+	require.Equal(t, "/static/src/app.js", static.URLFor("src/app.js"))
+	require.True(t, static.Has("app.js"))
+	require.Equal(t, []string{"src/admin/app.js", "src/app.js"}, static.Keys())
+}
+
+func TestPathStrip(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"app.js":"dist/app-1234.js"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithPathStrip("public/"))
+	require.Nil(t, err)
+	require.Equal(t, "dist/app-1234.js", static.currentMapping().Get("public/app.js"))
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("app.js"))
+	require.Equal(t, "/static/public/other.js", static.URLFor("public/other.js"))
+
+	loader = func(name string) ([]byte, error) { return []byte(`{"src/app.js":"dist/app-1234.js"}`), nil }
+	static, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader),
+		WithPathStrip("public/"), WithPathPrefix("src/"))
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("public/app.js"))
+}
+
+func TestManifestCompression(t *testing.T) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write([]byte(`{"js/app.js":"dist/app-1234.js"}`))
+	require.Nil(t, err)
+	require.Nil(t, writer.Close())
+	loader := func(name string) ([]byte, error) { return buf.Bytes(), nil }
+	gunzip := func(content []byte) ([]byte, error) {
+		reader, err := gzip.NewReader(bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.ReadAll(reader)
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithManifestCompression(gunzip))
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+
+	loader = func(name string) ([]byte, error) { return []byte(`{}`), nil }
+	_, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithManifestCompression(gunzip))
+	require.NotNil(t, err)
+}
+
+func TestManifestFormatVite(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{
+			"src/main.js": {"file": "assets/main.abc123.js", "src": "src/main.js", "isEntry": true,
+				"css": ["assets/main.abc123.css", "assets/vendor.def456.css"]},
+			"src/logo.svg": {"file": "assets/logo.789abc.svg", "src": "src/logo.svg"}
+		}`), nil
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithManifestFormat(ManifestFormatVite))
+	require.Nil(t, err)
+	require.Equal(t, "/static/assets/main.abc123.js", static.URLFor("src/main.js"))
+	require.Equal(t, "/static/assets/logo.789abc.svg", static.URLFor("src/logo.svg"))
+	require.Equal(t, []string{"assets/main.abc123.css", "assets/vendor.def456.css"}, static.LinkedAssets("src/main.js"))
+	require.Nil(t, static.LinkedAssets("src/logo.svg"))
+}
+
+func TestManifestFormatInvalid(t *testing.T) {
+	_, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithManifestFormat("parcel"))
+	require.NotNil(t, err)
+	_, err = NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithManifestFormat(ManifestFormatRev))
+	require.Nil(t, err)
+}
+
+func TestJSONManifestParser(t *testing.T) {
+	entries, err := JSONManifestParser{}.Parse([]byte(`{"js/app.js":"dist/app-1234.js", "js/broken.js": 1}`))
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{"js/app.js": "dist/app-1234.js"}, entries)
+	_, err = JSONManifestParser{}.Parse([]byte(`["js/app.js"]`))
+	require.Equal(t, errManifestNotObject, err)
+}
+
+// lineParser parses a YAML-like manifest with a "key: value" entry per line.
+type lineParser struct{}
+
+func (lineParser) Parse(content []byte) (map[string]string, error) {
+	manifest := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		parts := strings.SplitN(line, ": ", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid line %q", line)
+		}
+		manifest[parts[0]] = parts[1]
+	}
+	return manifest, nil
+}
+
+func TestManifestParser(t *testing.T) {
+	content := "js/app.js: dist/app-1234.js\ncss/style.css: dist/style-1234.css\n"
+	loader := func(name string) ([]byte, error) { return []byte(content), nil }
+	static, err := NewStatic("/static", "manifest.yaml", WithManifestLoader(loader),
+		WithManifestFormat(ManifestFormatYAML), WithManifestParser(lineParser{}))
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+	require.Equal(t, "/static/dist/style-1234.css", static.URLFor("css/style.css"))
+
+	content = "js/app.js"
+	require.True(t, errors.Is(static.ReloadManifest(), ErrInvalidManifest))
+
+	_, err = NewStatic("/static", "manifest.yaml", WithManifestLoader(loader), WithManifestFormat(ManifestFormatYAML))
+	require.True(t, errors.Is(err, errNoYAMLParser))
+}
+
+func TestManifestValidator(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	var validated interface{}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader),
+		WithManifestValidator(func(manifest interface{}) error {
+			validated = manifest
+			return nil
+		}))
+	require.Nil(t, err)
+	require.Equal(t, map[string]interface{}{"js/app.js": "dist/app-1234.js"}, validated)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+
+	_, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader),
+		WithManifestValidator(func(interface{}) error { return errors.New("too few entries") }))
+	require.Equal(t, "too few entries", err.Error())
+}
+
+func TestOptionError(t *testing.T) {
+	_, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithOptionError(errors.New("invalid")))
+	require.Equal(t, "invalid", err.Error())
+	_, err = NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithOptionError(nil))
+	require.Nil(t, err)
+}
@@ -0,0 +1,72 @@
+package asset
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+)
+
+var errImgDimensions = errors.New("img tag without width and height")
+
+// MediaTag returns HTML audio or video tag, depending on mediaType, which must be "audio" or "video".
+// See ScriptTag for additional information. Usually not used directly, but registered in template via FuncMap.
+func (st *Static) MediaTag(path string, mediaType string, attrs ...string) (template.HTML, error) {
+	defer st.profile(path)()
+	if mediaType != "audio" && mediaType != "video" {
+		return "", st.assetError(path, fmt.Errorf("unsupported media type %q", mediaType))
+	}
+	defaultAttrMap := map[string]string{}
+	st.addBuildTimestamp(defaultAttrMap)
+	attrMap, err := attrSliceToMap(attrs)
+	if err != nil {
+		return "", st.assetError(path, err)
+	}
+	resolved, err := st.resolveTag(path)
+	if err != nil {
+		return "", st.assetError(path, err)
+	}
+	if err := st.addSRI(defaultAttrMap, resolved); err != nil {
+		return "", st.assetError(path, err)
+	}
+	assetURL := st.resolvedURL(resolved)
+	if st.annotator != nil {
+		updateMap(defaultAttrMap, st.annotator(path, assetURL))
+	}
+	updateMap(defaultAttrMap, attrMap)
+	defaultAttrMap["src"] = assetURL
+	tag := fmt.Sprintf(`<%s %s></%s>`, mediaType, mapToAttrs(defaultAttrMap, !st.disableEscaping), mediaType)
+	if !st.firstRender(mediaType, path) {
+		return "", nil
+	}
+	return st.annotate(path, tag), nil
+}
+
+// ImgTag returns HTML img tag. Tags without the width and height attributes, which prevent layout shifts, are
+// preceded by an HTML comment with a warning, or with WithStrictMode make ImgTag return an error. See ScriptTag
+// for additional information. Usually not used directly, but registered in template via FuncMap.
+func (st *Static) ImgTag(path string, attrs ...string) (template.HTML, error) {
+	defer st.profile(path)()
+	defaultAttrMap := map[string]string{}
+	st.addBuildTimestamp(defaultAttrMap)
+	attrMap, err := attrSliceToMap(attrs)
+	if err != nil {
+		return "", st.assetError(path, err)
+	}
+	_, hasWidth := attrMap["width"]
+	_, hasHeight := attrMap["height"]
+	if st.strictMode && !(hasWidth && hasHeight) {
+		return "", st.assetError(path, errImgDimensions)
+	}
+	resolved, err := st.resolveTag(path)
+	if err != nil {
+		return "", st.assetError(path, err)
+	}
+	updateMap(defaultAttrMap, attrMap)
+	defaultAttrMap["src"] = st.resolvedURL(resolved)
+	tag := fmt.Sprintf(`<img %s/>`, mapToAttrs(defaultAttrMap, !st.disableEscaping))
+	if !(hasWidth && hasHeight) {
+		tag = fmt.Sprintf(`<!-- warning: %s: %v -->%s`, html.EscapeString(path), errImgDimensions, tag)
+	}
+	return st.annotate(path, tag), nil
+}
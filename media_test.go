@@ -0,0 +1,62 @@
+package asset
+
+import (
+	"github.com/stretchr/testify/require"
+	"html/template"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMediaTag(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"media/intro.mp4":"dist/intro-1234.mp4"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	tag, err := static.MediaTag("media/intro.mp4", "video", "autoplay", "autoplay", "muted", "muted")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<video autoplay="autoplay" muted="muted" src="/static/dist/intro-1234.mp4"></video>`), tag)
+	tag, err = static.MediaTag("media/intro.mp3", "audio", "controls", "controls")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<audio controls="controls" src="/static/media/intro.mp3"></audio>`), tag)
+	_, err = static.MediaTag("media/intro.mp4", "image")
+	require.NotNil(t, err)
+	_, err = static.MediaTag("media/intro.mp4", "video", "muted")
+	require.NotNil(t, err)
+}
+
+func TestMediaTagSri(t *testing.T) {
+	fsys := fstest.MapFS{"media/intro.mp3": {Data: []byte("alert(1)")}}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithFS(fsys), WithUseSri(true))
+	require.Nil(t, err)
+	tag, err := static.MediaTag("media/intro.mp3", "audio")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<audio crossorigin="anonymous" integrity="sha256-bhHHL3z2vDgxUt0W3dWQOrprscmda2Y5pLsLg4GF+pI=" src="/static/media/intro.mp3"></audio>`,
+	), tag)
+}
+
+func TestImgTag(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"img/logo.png":"dist/logo-1234.png"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	tag, err := static.ImgTag("img/logo.png", "alt", "Logo", "width", "120", "height", "40")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<img alt="Logo" height="40" src="/static/dist/logo-1234.png" width="120"/>`), tag)
+	tag, err = static.ImgTag("img/other.png")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<!-- warning: img/other.png: img tag without width and height --><img src="/static/img/other.png"/>`,
+	), tag)
+	_, err = static.ImgTag("img/logo.png", "alt")
+	require.NotNil(t, err)
+}
+
+func TestImgTagStrict(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"img/logo.png":"dist/logo-1234.png"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithStrictMode(true))
+	require.Nil(t, err)
+	_, err = static.ImgTag("img/logo.png", "width", "120")
+	require.NotNil(t, err)
+	tag, err := static.ImgTag("img/logo.png", "width", "120", "height", "40")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<img height="40" src="/static/dist/logo-1234.png" width="120"/>`), tag)
+}
@@ -0,0 +1,85 @@
+package asset
+
+import "errors"
+
+var errMergeUnsupported = errors.New("manifests can be merged only into a manifest mapping")
+
+// WithAdditionalManifests can be used in NewStatic to merge the manifests at paths, loaded with the manifest
+// loader, into the main one, e.g. when JS and CSS are built by separate pipelines. Entries of later manifests
+// overwrite the ones with the same keys. The manifests are merged again by ReloadManifest, into the mappings
+// replaced with SwapManifest and into the updates received with ManifestSchemePush.
+func WithAdditionalManifests(paths ...string) optionSetter {
+	return func(st *Static) { st.extraManifests = append(st.extraManifests, paths...) }
+}
+
+// MergeManifest loads the manifest at path with the manifest loader and merges its entries into the current
+// mapping, overwriting the ones with the same keys. If loading fails, the error is returned and the current
+// mapping is kept. Safe to call while templates are being rendered.
+func (st *Static) MergeManifest(path string) error {
+	if st.parent != nil {
+		return st.parent.MergeManifest(path)
+	}
+	mapping, err := st.createMapping(st.manifestLoader, path)
+	if err != nil {
+		return st.manifestError(err)
+	}
+	st.mappingLock.Lock()
+	defer st.mappingLock.Unlock()
+	merged, err := st.mergeMappings(st.mapping, mapping)
+	if err != nil {
+		return st.manifestError(err)
+	}
+	st.mapping = merged
+	return nil
+}
+
+// mergingBuilder returns a mapping builder merging the additional manifests into the mapping built by build.
+func (st *Static) mergingBuilder(build func() (StaticMapper, error)) func() (StaticMapper, error) {
+	return func() (StaticMapper, error) {
+		mapping, err := build()
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range st.extraManifests {
+			extra, err := st.createMapping(st.manifestLoader, path)
+			if err != nil {
+				return nil, err
+			}
+			if mapping, err = st.mergeMappings(mapping, extra); err != nil {
+				return nil, err
+			}
+		}
+		return mapping, nil
+	}
+}
+
+// mergeAdditionalManifests merges the manifests given with WithAdditionalManifests into mapping, e.g. one
+// received from the update channel.
+func (st *Static) mergeAdditionalManifests(mapping StaticMapper) (StaticMapper, error) {
+	return st.mergingBuilder(func() (StaticMapper, error) { return mapping, nil })()
+}
+
+// mergeMappings returns a new mapping with the entries of base and extra, preferring the ones of extra.
+func (st *Static) mergeMappings(base, extra StaticMapper) (StaticMapper, error) {
+	baseMap, ok := base.(*staticMap)
+	extraMap, extraOk := extra.(*staticMap)
+	if !ok || !extraOk {
+		return nil, errMergeUnsupported
+	}
+	merged := &staticMap{
+		innerMap:    make(map[string]string, len(baseMap.innerMap)+len(extraMap.innerMap)),
+		useMinified: baseMap.useMinified,
+	}
+	for _, sm := range []*staticMap{baseMap, extraMap} {
+		for key, value := range sm.innerMap {
+			merged.innerMap[key] = value
+		}
+		for key, files := range sm.linked {
+			if merged.linked == nil {
+				merged.linked = map[string][]string{}
+			}
+			merged.linked[key] = files
+		}
+	}
+	return st.configureMapping(merged), nil
+}
@@ -0,0 +1,93 @@
+package asset
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+func mergeLoader(manifests map[string]string) Loader {
+	return func(name string) ([]byte, error) {
+		content, ok := manifests[name]
+		if !ok {
+			return nil, fs.ErrNotExist
+		}
+		return []byte(content), nil
+	}
+}
+
+func TestMergeManifest(t *testing.T) {
+	loader := mergeLoader(map[string]string{
+		"manifest.json": `{"js/app.js":"dist/app-1234.js","css/style.css":"dist/style-1234.css"}`,
+		"css.json":      `{"css/style.css":"dist/style-5678.css","css/print.css":"dist/print-5678.css"}`,
+		"invalid.json":  `["css/style.css"]`,
+	})
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	clone := static.Clone()
+
+	require.Nil(t, clone.MergeManifest("css.json"))
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+	require.Equal(t, "/static/dist/style-5678.css", static.URLFor("css/style.css"))
+	require.Equal(t, "/static/dist/print-5678.css", static.URLFor("css/print.css"))
+
+	err = static.MergeManifest("invalid.json")
+	require.True(t, errors.Is(err, ErrInvalidManifest))
+	require.True(t, errors.Is(static.MergeManifest("missing.json"), fs.ErrNotExist))
+	require.Equal(t, "/static/dist/style-5678.css", static.URLFor("css/style.css"))
+}
+
+func TestAdditionalManifests(t *testing.T) {
+	manifests := map[string]string{
+		"manifest.json": `{"js/app.js":"dist/app-1234.js","css/style.css":"dist/style-1234.css"}`,
+		"css.json":      `{"css/style.css":"dist/style-5678.css"}`,
+		"print.json":    `{"css/style.css":"dist/style-9012.css","css/print.css":"dist/print-9012.css"}`,
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(mergeLoader(manifests)),
+		WithAdditionalManifests("css.json", "print.json"))
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+	require.Equal(t, "/static/dist/style-9012.css", static.URLFor("css/style.css"))
+	require.Equal(t, "/static/dist/print-9012.css", static.URLFor("css/print.css"))
+
+	manifests["print.json"] = `{"css/print.css":"dist/print-3456.css"}`
+	require.Nil(t, static.ReloadManifest())
+	require.Equal(t, "/static/dist/style-5678.css", static.URLFor("css/style.css"))
+	require.Equal(t, "/static/dist/print-3456.css", static.URLFor("css/print.css"))
+
+	manifests["css.json"] = `"invalid"`
+	_, err = NewStatic("/static", "manifest.json", WithManifestLoader(mergeLoader(manifests)),
+		WithAdditionalManifests("css.json"))
+	require.True(t, errors.Is(err, ErrInvalidManifest))
+}
+
+func TestAdditionalManifestsSwap(t *testing.T) {
+	manifests := map[string]string{
+		"manifest.json": `{"js/app.js":"dist/app-1234.js"}`,
+		"css.json":      `{"css/style.css":"dist/style-5678.css"}`,
+	}
+	var reported error
+	updates := make(chan []byte)
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(mergeLoader(manifests)),
+		WithAdditionalManifests("css.json"), WithManifestScheme(ManifestSchemePush), WithManifestUpdateChan(updates),
+		WithOnError(func(err error) { reported = err }))
+	require.Nil(t, err)
+	defer static.Stop()
+
+	updates <- []byte(`{"js/app.js":"dist/app-9012.js"}`)
+	require.Eventually(t, func() bool {
+		return static.URLFor("js/app.js") == "/static/dist/app-9012.js"
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, "/static/dist/style-5678.css", static.URLFor("css/style.css"))
+
+	static.Clone().SwapManifest(static.mappingFromMap(map[string]string{"js/app.js": "dist/app-3456.js"}))
+	require.Equal(t, "/static/dist/app-3456.js", static.URLFor("js/app.js"))
+	require.Equal(t, "/static/dist/style-5678.css", static.URLFor("css/style.css"))
+	require.Nil(t, reported)
+
+	static.SwapManifest(mapperFunc(func(name string) string { return "cdn/" + name }))
+	require.True(t, errors.Is(reported, errMergeUnsupported))
+	require.Equal(t, "/static/dist/app-3456.js", static.URLFor("js/app.js"))
+}
@@ -0,0 +1,18 @@
+package asset
+
+import (
+	"html/template"
+)
+
+// ModuleScriptTag returns HTML script tag with type="module", for ES modules. See ScriptTag for additional
+// information. Usually not used directly, but registered in template via FuncMap.
+func (st *Static) ModuleScriptTag(path string, attrs ...string) (template.HTML, error) {
+	return st.scriptTag(path, "", "module", attrs)
+}
+
+// NomoduleScriptTag returns HTML script tag with the nomodule boolean attribute, for browsers not supporting
+// ES modules; together with ModuleScriptTag it enables differential loading. See ScriptTag for additional
+// information. Usually not used directly, but registered in template via FuncMap.
+func (st *Static) NomoduleScriptTag(path string, attrs ...string) (template.HTML, error) {
+	return st.scriptTag(path, "", "text/javascript", attrs, "nomodule")
+}
@@ -0,0 +1,44 @@
+package asset
+
+import (
+	"github.com/stretchr/testify/require"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+func TestModuleScriptTags(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.mjs":"dist/app-1234.mjs","js/app.js":"dist/app-5678.js"}`), nil
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	tmpl, err := static.Template().Parse(`{{ modulescripttag "js/app.mjs" "async" "async" }}{{ nomodulescripttag "js/app.js" }}`)
+	require.Nil(t, err)
+	var out strings.Builder
+	require.Nil(t, tmpl.Execute(&out, nil))
+	require.Equal(t, `<script async="async" src="/static/dist/app-1234.mjs" type="module"></script>`+
+		`<script nomodule src="/static/dist/app-5678.js" type="text/javascript"></script>`, out.String())
+}
+
+func TestNomoduleScriptTagAnnotated(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithHTMLCommentAnnotation(true))
+	require.Nil(t, err)
+	tag, err := static.NomoduleScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<!-- asset: js/app.js --><script nomodule src="/static/js/app.js" type="text/javascript"></script><!-- /asset -->`,
+	), tag)
+	_, err = static.NomoduleScriptTag("js/app.js", "defer")
+	require.NotNil(t, err)
+}
+
+func TestNomoduleScriptTagAMP(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithOutputFormat(OutputFormatAMP))
+	require.Nil(t, err)
+	tag, err := static.NomoduleScriptTag("js/app.js", "layout", "container")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<amp-script nomodule layout="container" src="/static/js/app.js"></amp-script>`,
+	), tag)
+}
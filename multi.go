@@ -0,0 +1,45 @@
+package asset
+
+import (
+	"sync"
+)
+
+// StaticConfig holds NewStatic arguments for NewStaticMulti. It can be created with NewStaticConfig.
+type StaticConfig struct {
+	URLPrefix    string
+	ManifestPath string
+	Options      []optionSetter
+}
+
+// NewStaticConfig returns StaticConfig with the given NewStatic arguments.
+func NewStaticConfig(urlPrefix string, manifestPath string, options ...optionSetter) StaticConfig {
+	return StaticConfig{URLPrefix: urlPrefix, ManifestPath: manifestPath, Options: options}
+}
+
+// NewStaticMulti creates a Static instance for every config in parallel. Instances are returned in
+// the order of configs. If any of them fails, the error of the first failing config is returned and
+// the instances created for the other configs are stopped.
+func NewStaticMulti(configs ...StaticConfig) ([]*Static, error) {
+	statics := make([]*Static, len(configs))
+	errs := make([]error, len(configs))
+	var wg sync.WaitGroup
+	for i, config := range configs {
+		wg.Add(1)
+		go func(i int, config StaticConfig) {
+			defer wg.Done()
+			statics[i], errs[i] = NewStatic(config.URLPrefix, config.ManifestPath, config.Options...)
+		}(i, config)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			for _, static := range statics {
+				if static != nil {
+					static.Stop()
+				}
+			}
+			return nil, err
+		}
+	}
+	return statics, nil
+}
@@ -0,0 +1,52 @@
+package asset
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewStaticMulti(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/` + name + `.js"}`), nil }
+	statics, err := NewStaticMulti(
+		NewStaticConfig("/admin", "admin", WithManifestLoader(loader)),
+		NewStaticConfig("/shop", "shop", WithManifestLoader(loader)),
+	)
+	require.Nil(t, err)
+	require.Len(t, statics, 2)
+	require.Equal(t, "/admin/dist/admin.js", statics[0].URLFor("js/app.js"))
+	require.Equal(t, "/shop/dist/shop.js", statics[1].URLFor("js/app.js"))
+}
+
+func TestNewStaticMultiError(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return nil, errors.New(name) }
+	statics, err := NewStaticMulti(
+		StaticConfig{URLPrefix: "/static", Options: []optionSetter{WithManifestLoader(nil)}},
+		NewStaticConfig("/admin", "admin", WithManifestLoader(loader)),
+		NewStaticConfig("/shop", "shop", WithManifestLoader(loader)),
+	)
+	require.Nil(t, statics)
+	require.Equal(t, "admin", err.Error())
+}
+
+func TestNewStaticMultiErrorStops(t *testing.T) {
+	var reloads int32
+	loader := func(name string) ([]byte, error) {
+		if name == "admin" {
+			return nil, errors.New(name)
+		}
+		atomic.AddInt32(&reloads, 1)
+		return []byte(`{}`), nil
+	}
+	_, err := NewStaticMulti(
+		NewStaticConfig("/shop", "shop", WithManifestLoader(loader), WithManifestRefreshInterval(time.Millisecond)),
+		NewStaticConfig("/admin", "admin", WithManifestLoader(loader)),
+	)
+	require.Equal(t, "admin", err.Error())
+	time.Sleep(10 * time.Millisecond)
+	count := atomic.LoadInt32(&reloads)
+	time.Sleep(20 * time.Millisecond)
+	require.Equal(t, count, atomic.LoadInt32(&reloads))
+}
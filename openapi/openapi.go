@@ -0,0 +1,67 @@
+// Package openapi documents the HTTP handlers of go-asset-helper in OpenAPI specifications built with
+// kin-openapi.
+package openapi
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rsniezynski/go-asset-helper"
+	"net/http"
+)
+
+const (
+	// ManifestPath is the path under which ManifestHandler is documented.
+	ManifestPath = "/manifest"
+	// HealthPath is the path under which HealthCheckHandler is documented.
+	HealthPath = "/health"
+)
+
+// WithOpenAPI can be used in asset.NewStatic to add the paths served by ManifestHandler and HealthCheckHandler
+// to spec.
+func WithOpenAPI(spec *openapi3.T) func(*asset.Static) {
+	return func(*asset.Static) {
+		if spec.Paths == nil {
+			spec.Paths = openapi3.NewPaths()
+		}
+		spec.Paths.Set(ManifestPath, manifestPathItem())
+		spec.Paths.Set(HealthPath, healthPathItem())
+	}
+}
+
+func manifestPathItem() *openapi3.PathItem {
+	manifestSchema := openapi3.NewObjectSchema().WithAdditionalProperties(openapi3.NewStringSchema())
+	operation := openapi3.NewOperation()
+	operation.OperationID = "getAssetManifest"
+	operation.Summary = "Returns the asset manifest"
+	operation.Responses = openapi3.NewResponses(
+		withStatus(http.StatusOK, openapi3.NewResponse().
+			WithDescription("Mapping from asset paths to versioned files").
+			WithJSONSchema(manifestSchema)),
+		withStatus(http.StatusUnauthorized, openapi3.NewResponse().WithDescription("Unauthorized")),
+		withStatus(http.StatusInternalServerError, openapi3.NewResponse().WithDescription("Manifest not available")),
+	)
+	return &openapi3.PathItem{Get: operation}
+}
+
+func healthPathItem() *openapi3.PathItem {
+	healthSchema := openapi3.NewObjectSchema().
+		WithProperty("status", openapi3.NewStringSchema().WithEnum("ok", "unavailable")).
+		WithProperty("assets", openapi3.NewIntegerSchema()).
+		WithProperty("cdn", openapi3.NewStringSchema().WithEnum("primary", "fallback"))
+	healthSchema.Required = []string{"status"}
+	operation := openapi3.NewOperation()
+	operation.OperationID = "getAssetHealth"
+	operation.Summary = "Reports whether the asset manifest is loaded"
+	operation.Responses = openapi3.NewResponses(
+		withStatus(http.StatusOK, openapi3.NewResponse().
+			WithDescription("Manifest loaded").
+			WithJSONSchema(healthSchema)),
+		withStatus(http.StatusServiceUnavailable, openapi3.NewResponse().
+			WithDescription("Manifest not loaded").
+			WithJSONSchema(healthSchema)),
+	)
+	return &openapi3.PathItem{Get: operation}
+}
+
+func withStatus(status int, response *openapi3.Response) openapi3.NewResponsesOption {
+	return openapi3.WithStatus(status, &openapi3.ResponseRef{Value: response})
+}
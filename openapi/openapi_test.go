@@ -0,0 +1,22 @@
+package openapi
+
+import (
+	"context"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rsniezynski/go-asset-helper"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWithOpenAPI(t *testing.T) {
+	spec := &openapi3.T{OpenAPI: "3.0.3", Info: &openapi3.Info{Title: "app", Version: "1.0"}}
+	_, err := asset.NewStatic("/static", "manifest.json", asset.WithManifestLoader(nil), WithOpenAPI(spec))
+	require.Nil(t, err)
+	require.Nil(t, spec.Validate(context.Background()))
+	item := spec.Paths.Value(ManifestPath)
+	require.NotNil(t, item)
+	require.NotNil(t, item.Get.Responses.Status(200))
+	item = spec.Paths.Value(HealthPath)
+	require.NotNil(t, item)
+	require.NotNil(t, item.Get.Responses.Status(503))
+}
@@ -0,0 +1,224 @@
+package asset
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+const defaultTTFBHintCount = 3
+
+// PreloadAsset describes an asset to be preloaded.
+type PreloadAsset struct {
+	// Path is the asset path, resolved through the manifest.
+	Path string
+	// As is the type of the asset, e.g. "script", "style" or "font".
+	As string
+	// Crossorigin is the CORS setting, "anonymous" or "use-credentials"; omitted if empty.
+	Crossorigin string
+	// Nonce is the CSP nonce; omitted if empty.
+	Nonce string
+}
+
+// GeneratePreloadHeaders returns a Link header preloading assets, e.g. to be sent with 103 Early Hints.
+// Without assets the header is empty.
+func (st *Static) GeneratePreloadHeaders(assets []PreloadAsset) http.Header {
+	if len(assets) == 0 {
+		return http.Header{}
+	}
+	links := make([]string, 0, len(assets))
+	for _, asset := range assets {
+		link := fmt.Sprintf("<%s>; rel=preload", st.URLFor(asset.Path))
+		if asset.As != "" {
+			link += "; as=" + asset.As
+		}
+		if asset.Crossorigin != "" {
+			link += "; crossorigin=" + asset.Crossorigin
+		}
+		if asset.Nonce != "" {
+			link += "; nonce=" + asset.Nonce
+		}
+		links = append(links, link)
+	}
+	return http.Header{"Link": []string{strings.Join(links, ", ")}}
+}
+
+// ServerPushLinks returns the value of a Link header preloading the assets, e.g. to trigger HTTP/2 server push
+// from a handler before the response is written. The types are inferred from the extensions, and with
+// WithUseSri(true) the integrity of the assets is included.
+func (st *Static) ServerPushLinks(paths ...string) (string, error) {
+	links := make([]string, 0, len(paths))
+	for _, assetPath := range paths {
+		entry := preloadEntry(assetPath)
+		resolved := st.resolve(assetPath)
+		link := fmt.Sprintf("<%s>; rel=preload", st.resolvedURL(resolved))
+		if entry.As != "" {
+			link += "; as=" + entry.As
+		}
+		if st.useSri {
+			hash, err := st.sri(stripQuery(resolved))
+			if err != nil {
+				return "", st.assetError(assetPath, err)
+			}
+			link += fmt.Sprintf("; integrity=%q; crossorigin=anonymous", hash)
+		} else if entry.Crossorigin != "" {
+			link += "; crossorigin=" + entry.Crossorigin
+		}
+		links = append(links, link)
+	}
+	return strings.Join(links, ", "), nil
+}
+
+// PreloadEntry is a PreloadAsset suggested by TTFBHints.
+type PreloadEntry = PreloadAsset
+
+// WithUsageTracking can be used in NewStatic to count how many times every asset is looked up, which is
+// the basis for TTFBHints. Disabled by default.
+func WithUsageTracking(enabled bool) optionSetter {
+	return func(st *Static) { st.trackUsage = enabled }
+}
+
+// WithTTFBHintCount can be used in NewStatic to change the number of assets returned by TTFBHints (3 by default).
+func WithTTFBHintCount(n int) optionSetter {
+	return func(st *Static) { st.ttfbHintCount = n }
+}
+
+// TTFBHints returns preload entries for the most often looked up assets, to be passed e.g. to
+// GeneratePreloadHeaders. Requires WithUsageTracking; returns nothing otherwise.
+func (st *Static) TTFBHints() []PreloadEntry {
+	type usage struct {
+		path  string
+		count int64
+	}
+	usages := []usage{}
+	st.usage.Range(func(key, value interface{}) bool {
+		usages = append(usages, usage{key.(string), atomic.LoadInt64(value.(*int64))})
+		return true
+	})
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].count != usages[j].count {
+			return usages[i].count > usages[j].count
+		}
+		return usages[i].path < usages[j].path
+	})
+	entries := []PreloadEntry{}
+	for i := 0; i < len(usages) && i < st.ttfbHintCount; i++ {
+		entries = append(entries, preloadEntry(usages[i].path))
+	}
+	return entries
+}
+
+// countUsage counts a lookup of the asset if usage tracking is enabled.
+func (st *Static) countUsage(assetPath string) {
+	if !st.trackUsage {
+		return
+	}
+	value, _ := st.usage.LoadOrStore(assetPath, new(int64))
+	atomic.AddInt64(value.(*int64), 1)
+}
+
+// preloadEntry returns a preload entry for the asset with the type inferred from its extension.
+func preloadEntry(assetPath string) PreloadEntry {
+	entry := PreloadEntry{Path: assetPath}
+	switch strings.ToLower(path.Ext(assetPath)) {
+	case ".js", ".mjs":
+		entry.As = "script"
+	case ".css":
+		entry.As = "style"
+	case ".woff", ".woff2", ".ttf", ".otf", ".eot":
+		entry.As, entry.Crossorigin = "font", "anonymous"
+	case ".png", ".jpg", ".jpeg", ".gif", ".svg", ".webp", ".avif", ".ico":
+		entry.As = "image"
+	}
+	return entry
+}
+
+// fontMimeTypes maps font extensions to the MIME types used in the type attribute of font preload tags.
+var fontMimeTypes = map[string]string{
+	".woff":  "font/woff",
+	".woff2": "font/woff2",
+	".ttf":   "font/ttf",
+	".otf":   "font/otf",
+	".eot":   "application/vnd.ms-fontobject",
+}
+
+// fontMimeType returns the MIME type of a resolved font path.
+func fontMimeType(resolved string) (string, bool) {
+	mimeType, ok := fontMimeTypes[strings.ToLower(path.Ext(stripQuery(resolved)))]
+	return mimeType, ok
+}
+
+var errPreloadAsRequired = errors.New("preload tag requires the as argument")
+
+// PreloadTag returns HTML link tag with rel="preload"; as is the type of the asset, e.g. "script", "style",
+// "font" or "image". Font preloads get the type attribute derived from the resolved extension.
+// See ScriptTag for additional information. Usually not used directly, but registered in template via FuncMap.
+func (st *Static) PreloadTag(path string, as string, attrs ...string) (template.HTML, error) {
+	defer st.profile(path)()
+	if as == "" {
+		return "", st.assetError(path, errPreloadAsRequired)
+	}
+	return st.relLinkTag(path, map[string]string{"rel": "preload", "as": as}, attrs)
+}
+
+// ModulePreloadTag returns HTML link tag with rel="modulepreload", which unlike PreloadTag with as="script"
+// also parses and compiles ES modules. See ScriptTag for additional information. Usually not used directly,
+// but registered in template via FuncMap.
+func (st *Static) ModulePreloadTag(path string, attrs ...string) (template.HTML, error) {
+	defer st.profile(path)()
+	return st.relLinkTag(path, map[string]string{"rel": "modulepreload"}, attrs)
+}
+
+// relLinkTag renders a link tag of the asset at path with the rel and other default attributes in defaultAttrMap.
+func (st *Static) relLinkTag(path string, defaultAttrMap map[string]string, attrs []string) (template.HTML, error) {
+	rel := defaultAttrMap["rel"]
+	st.addBuildTimestamp(defaultAttrMap)
+	attrMap, err := attrSliceToMap(attrs)
+	if err != nil {
+		return "", st.assetError(path, err)
+	}
+	resolved, err := st.resolveTag(path)
+	if err != nil {
+		return "", st.assetError(path, err)
+	}
+	if defaultAttrMap["as"] == "font" {
+		if mimeType, ok := fontMimeType(resolved); ok {
+			defaultAttrMap["type"] = mimeType
+		}
+	}
+	if err := st.addSRI(defaultAttrMap, resolved); err != nil {
+		return "", st.assetError(path, err)
+	}
+	updateMap(defaultAttrMap, attrMap)
+	defaultAttrMap["href"] = st.resolvedURL(resolved)
+	tag := fmt.Sprintf(`<link %s/>`, mapToAttrs(defaultAttrMap, !st.disableEscaping))
+	if !st.firstRender(rel, path) {
+		return "", nil
+	}
+	return st.annotate(path, tag), nil
+}
+
+// LinkTagPreload returns HTML link tag with rel="preload" like PreloadTag, adding crossorigin="anonymous" to
+// font preloads unless attrs set crossorigin, as fonts are always fetched in CORS mode and a preload without
+// it would be fetched twice.
+func (st *Static) LinkTagPreload(path string, as string, attrs ...string) (template.HTML, error) {
+	if as == "font" && !hasAttr(attrs, "crossorigin") {
+		attrs = append([]string{"crossorigin", "anonymous"}, attrs...)
+	}
+	return st.PreloadTag(path, as, attrs...)
+}
+
+// hasAttr reports whether attrs, pairs of attribute names and values, set name.
+func hasAttr(attrs []string, name string) bool {
+	for i := 0; i < len(attrs); i += 2 {
+		if attrs[i] == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package asset
+
+import (
+	"github.com/stretchr/testify/require"
+	"html/template"
+	"net/http"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGeneratePreloadHeaders(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	header := static.GeneratePreloadHeaders([]PreloadAsset{
+		{Path: "js/app.js", As: "script", Nonce: "abc"},
+		{Path: "fonts/font.woff2", As: "font", Crossorigin: "anonymous"},
+	})
+	require.Equal(t, http.Header{"Link": []string{
+		"</static/dist/app-1234.js>; rel=preload; as=script; nonce=abc, " +
+			"</static/fonts/font.woff2>; rel=preload; as=font; crossorigin=anonymous",
+	}}, header)
+	require.Equal(t, http.Header{}, static.GeneratePreloadHeaders(nil))
+	require.Equal(t, http.Header{}, static.GeneratePreloadHeaders([]PreloadAsset{}))
+}
+
+func TestServerPushLinks(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"dist/app-1234.js","css/style.css":"dist/style-1234.css"}`), nil
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	links, err := static.ServerPushLinks("js/app.js", "css/style.css", "fonts/font.woff2", "data.json")
+	require.Nil(t, err)
+	require.Equal(t, "</static/dist/app-1234.js>; rel=preload; as=script, "+
+		"</static/dist/style-1234.css>; rel=preload; as=style, "+
+		"</static/fonts/font.woff2>; rel=preload; as=font; crossorigin=anonymous, "+
+		"</static/data.json>; rel=preload", links)
+	links, err = static.ServerPushLinks()
+	require.Nil(t, err)
+	require.Equal(t, "", links)
+}
+
+func TestServerPushLinksSri(t *testing.T) {
+	fsys := fstest.MapFS{"js/app.js": {Data: []byte("alert(1)")}}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithFS(fsys), WithUseSri(true))
+	require.Nil(t, err)
+	links, err := static.ServerPushLinks("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, `</static/js/app.js>; rel=preload; as=script; `+
+		`integrity="sha256-bhHHL3z2vDgxUt0W3dWQOrprscmda2Y5pLsLg4GF+pI="; crossorigin=anonymous`, links)
+	_, err = static.ServerPushLinks("js/missing.js")
+	require.NotNil(t, err)
+}
+
+func TestTTFBHints(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithUsageTracking(true))
+	require.Nil(t, err)
+	for path, count := range map[string]int{"js/app.js": 3, "css/style.css": 5, "fonts/font.woff2": 3, "img/logo.png": 1} {
+		for i := 0; i < count; i++ {
+			static.URLFor(path)
+		}
+	}
+	require.Equal(t, []PreloadEntry{
+		{Path: "css/style.css", As: "style"},
+		{Path: "fonts/font.woff2", As: "font", Crossorigin: "anonymous"},
+		{Path: "js/app.js", As: "script"},
+	}, static.TTFBHints())
+
+	static, err = NewStatic(
+		"/static", "manifest.json", WithManifestLoader(nil), WithUsageTracking(true), WithTTFBHintCount(1),
+	)
+	require.Nil(t, err)
+	static.URLFor("js/app.js")
+	require.Equal(t, []PreloadEntry{{Path: "js/app.js", As: "script"}}, static.TTFBHints())
+}
+
+func TestTTFBHintsNoTracking(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil))
+	require.Nil(t, err)
+	static.URLFor("js/app.js")
+	require.Equal(t, []PreloadEntry{}, static.TTFBHints())
+}
+
+func TestPreloadTag(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.min.js":"dist/app-1234.min.js","fonts/font.woff2":"dist/font-1234.woff2"}`), nil
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithUseMinified(true))
+	require.Nil(t, err)
+	tag, err := static.PreloadTag("js/app.js", "script")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<link as="script" href="/static/dist/app-1234.min.js" rel="preload"/>`), tag)
+	tag, err = static.PreloadTag("fonts/font.woff2", "font", "crossorigin", "anonymous")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<link as="font" crossorigin="anonymous" href="/static/dist/font-1234.woff2" rel="preload" type="font/woff2"/>`,
+	), tag)
+	_, err = static.PreloadTag("js/app.js", "")
+	require.NotNil(t, err)
+	_, err = static.PreloadTag("js/app.js", "script", "defer")
+	require.NotNil(t, err)
+}
+
+func TestPreloadTagSri(t *testing.T) {
+	fsys := fstest.MapFS{"js/app.js": {Data: []byte("alert(1)")}}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithFS(fsys), WithUseSri(true))
+	require.Nil(t, err)
+	tag, err := static.PreloadTag("js/app.js", "script")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<link as="script" crossorigin="anonymous" href="/static/js/app.js" `+
+			`integrity="sha256-bhHHL3z2vDgxUt0W3dWQOrprscmda2Y5pLsLg4GF+pI=" rel="preload"/>`,
+	), tag)
+}
+
+func TestModulePreloadTag(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.mjs":"dist/app-1234.mjs"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	tag, err := static.ModulePreloadTag("js/app.mjs")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<link href="/static/dist/app-1234.mjs" rel="modulepreload"/>`), tag)
+	tag, err = static.ModulePreloadTag("js/app.mjs", "crossorigin", "use-credentials", "rel", "preload")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<link crossorigin="use-credentials" href="/static/dist/app-1234.mjs" rel="preload"/>`,
+	), tag)
+	_, err = static.ModulePreloadTag("js/app.mjs", "crossorigin")
+	require.NotNil(t, err)
+}
+
+func TestModulePreloadTagSri(t *testing.T) {
+	fsys := fstest.MapFS{"js/app.js": {Data: []byte("alert(1)")}}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithFS(fsys), WithUseSri(true))
+	require.Nil(t, err)
+	tag, err := static.ModulePreloadTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<link crossorigin="anonymous" href="/static/js/app.js" `+
+			`integrity="sha256-bhHHL3z2vDgxUt0W3dWQOrprscmda2Y5pLsLg4GF+pI=" rel="modulepreload"/>`,
+	), tag)
+}
+
+func TestLinkTagPreload(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil))
+	require.Nil(t, err)
+	tag, err := static.LinkTagPreload("fonts/font.woff2", "font")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<link as="font" crossorigin="anonymous" href="/static/fonts/font.woff2" rel="preload" type="font/woff2"/>`,
+	), tag)
+	tag, err = static.LinkTagPreload("fonts/font.woff2", "font", "crossorigin", "use-credentials")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<link as="font" crossorigin="use-credentials" href="/static/fonts/font.woff2" rel="preload" type="font/woff2"/>`,
+	), tag)
+	tag, err = static.LinkTagPreload("js/app.js", "script")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(`<link as="script" href="/static/js/app.js" rel="preload"/>`), tag)
+	_, err = static.LinkTagPreload("fonts/font.woff2", "font", "media")
+	require.NotNil(t, err)
+}
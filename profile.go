@@ -0,0 +1,72 @@
+package asset
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AssetProfiler measures rendering of asset tags. Start is called with the asset path when rendering starts
+// and returns a function called when it ends.
+type AssetProfiler interface {
+	Start(path string) func()
+}
+
+// WithProfiler can be used in NewStatic to profile rendering of tags by ScriptTag and LinkTag.
+func WithProfiler(profiler AssetProfiler) optionSetter {
+	return func(st *Static) { st.profiler = profiler }
+}
+
+// profile starts profiling rendering of an asset and returns the function ending it.
+func (st *Static) profile(path string) func() {
+	if st.profiler == nil {
+		return func() {}
+	}
+	return st.profiler.Start(path)
+}
+
+// HTTPProfiler is an AssetProfiler recording the number of renders and their total duration per asset,
+// which are served as JSON by ProfileHandler. The zero value is ready to use.
+type HTTPProfiler struct {
+	profiles sync.Map
+}
+
+type assetProfile struct {
+	Calls   int64 `json:"calls"`
+	TotalNs int64 `json:"total_ns"`
+}
+
+// Start implements AssetProfiler.
+func (p *HTTPProfiler) Start(path string) func() {
+	start := time.Now()
+	return func() {
+		value, _ := p.profiles.LoadOrStore(path, &assetProfile{})
+		profile := value.(*assetProfile)
+		atomic.AddInt64(&profile.Calls, 1)
+		atomic.AddInt64(&profile.TotalNs, int64(time.Since(start)))
+	}
+}
+
+// ProfileHandler returns an http.Handler serving the recorded profiles as JSON object keyed by asset path.
+func (p *HTTPProfiler) ProfileHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		profiles := map[string]assetProfile{}
+		p.profiles.Range(func(key, value interface{}) bool {
+			profile := value.(*assetProfile)
+			profiles[key.(string)] = assetProfile{
+				Calls:   atomic.LoadInt64(&profile.Calls),
+				TotalNs: atomic.LoadInt64(&profile.TotalNs),
+			}
+			return true
+		})
+		content, err := json.Marshal(profiles)
+		if err != nil {
+			defaultErrorHandler(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(content)
+	})
+}
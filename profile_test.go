@@ -0,0 +1,47 @@
+package asset
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"net/http/httptest"
+	"testing"
+)
+
+type recordingProfiler struct {
+	started []string
+	stopped []string
+}
+
+func (p *recordingProfiler) Start(path string) func() {
+	p.started = append(p.started, path)
+	return func() { p.stopped = append(p.stopped, path) }
+}
+
+func TestProfiler(t *testing.T) {
+	profiler := &recordingProfiler{}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithProfiler(profiler))
+	require.Nil(t, err)
+	_, err = static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	_, err = static.LinkTag("css/style.css", "odd")
+	require.NotNil(t, err)
+	require.Equal(t, []string{"js/app.js", "css/style.css"}, profiler.started)
+	require.Equal(t, []string{"js/app.js", "css/style.css"}, profiler.stopped)
+}
+
+func TestHTTPProfiler(t *testing.T) {
+	profiler := &HTTPProfiler{}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithProfiler(profiler))
+	require.Nil(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = static.ScriptTag("js/app.js")
+		require.Nil(t, err)
+	}
+	recorder := httptest.NewRecorder()
+	profiler.ProfileHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/profile", nil))
+	require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	var profiles map[string]assetProfile
+	require.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &profiles))
+	require.Len(t, profiles, 1)
+	require.Equal(t, int64(2), profiles["js/app.js"].Calls)
+}
@@ -0,0 +1,55 @@
+package asset
+
+import (
+	"errors"
+)
+
+// ManifestScheme selects how manifest updates reach a Static instance.
+type ManifestScheme int
+
+const (
+	// ManifestSchemePull loads the manifest using the configured loader. This is the default.
+	ManifestSchemePull ManifestScheme = iota
+	// ManifestSchemePush receives manifest updates from the channel given with WithManifestUpdateChan,
+	// e.g. fed from a message queue subscription.
+	ManifestSchemePush
+)
+
+// WithManifestScheme can be used in NewStatic to select the manifest update model. With ManifestSchemePush
+// the manifest is loaded as usual on creation and then replaced with every update received from the channel
+// given with WithManifestUpdateChan, which is required in this case.
+func WithManifestScheme(scheme ManifestScheme) optionSetter {
+	return func(st *Static) { st.manifestScheme = scheme }
+}
+
+// WithManifestUpdateChan can be used together with WithManifestScheme(ManifestSchemePush) to provide
+// the channel delivering new manifest contents. Updates that can't be parsed are dropped and the current
+// manifest is kept. Receiving stops when the channel is closed or Stop is called.
+func WithManifestUpdateChan(ch <-chan []byte) optionSetter {
+	return func(st *Static) { st.updates = ch }
+}
+
+// receiveManifests parses manifests received from the update channel and swaps them in.
+func (st *Static) receiveManifests() {
+	for {
+		select {
+		case content, ok := <-st.updates:
+			if !ok {
+				return
+			}
+			load := func(string) ([]byte, error) { return content, nil }
+			mapping, err := st.createMapping(load, st.manifestPath)
+			if err == nil {
+				mapping, err = st.mergeAdditionalManifests(mapping)
+			}
+			if err == nil {
+				st.replaceMapping(mapping)
+			}
+			st.reloaded(err)
+		case <-st.refresher.stop:
+			return
+		}
+	}
+}
+
+var errNoUpdateChan = errors.New("ManifestSchemePush requires WithManifestUpdateChan")
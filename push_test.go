@@ -0,0 +1,64 @@
+package asset
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestManifestSchemePush(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	updates := make(chan []byte)
+	static, err := NewStatic(
+		"/static", "manifest.json", WithManifestLoader(loader),
+		WithManifestScheme(ManifestSchemePush), WithManifestUpdateChan(updates),
+	)
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+
+	updates <- []byte("garbage")
+	updates <- []byte(`["js/app.js"]`)
+	updates <- []byte(`{"js/app.js":"dist/app-5678.js"}`)
+	close(updates)
+	require.Eventually(t, func() bool {
+		return static.URLFor("js/app.js") == "/static/dist/app-5678.js"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestManifestSchemePushStop(t *testing.T) {
+	updates := make(chan []byte)
+	static, err := NewStatic(
+		"/static", "manifest.json", WithManifestLoader(nil),
+		WithManifestScheme(ManifestSchemePush), WithManifestUpdateChan(updates),
+	)
+	require.Nil(t, err)
+	static.Stop()
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case updates <- []byte(`{"js/app.js":"dist/app-5678.js"}`):
+		t.Fatal("update received after Stop")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestManifestSchemePushNoChan(t *testing.T) {
+	_, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithManifestScheme(ManifestSchemePush))
+	require.Equal(t, errNoUpdateChan, err)
+}
+
+func TestManifestSchemePullIgnoresChan(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	updates := make(chan []byte, 1)
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithManifestUpdateChan(updates))
+	require.Nil(t, err)
+	updates <- []byte(`{"js/app.js":"dist/app-5678.js"}`)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+	require.Len(t, updates, 1)
+}
+
+func TestSwapManifest(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil))
+	require.Nil(t, err)
+	static.SwapManifest(mapperFunc(func(name string) string { return "dist/" + name }))
+	require.Equal(t, "/static/dist/js/app.js", static.URLFor("js/app.js"))
+}
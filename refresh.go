@@ -0,0 +1,50 @@
+package asset
+
+import (
+	"sync"
+	"time"
+)
+
+// WithManifestRefreshInterval can be used in NewStatic to reload the manifest with ReloadManifest every d,
+// e.g. when it's kept in a shared file system. The reloading goroutine runs until Stop is called.
+func WithManifestRefreshInterval(d time.Duration) optionSetter {
+	return func(st *Static) { st.refreshInterval = d }
+}
+
+// WithRefreshErrorHandler can be used together with WithManifestRefreshInterval to provide a function
+// called with the errors of periodic reloads. The current manifest is kept when a reload fails.
+func WithRefreshErrorHandler(fn func(error)) optionSetter {
+	return func(st *Static) { st.refreshOnError = fn }
+}
+
+// refresher stops the goroutines updating the manifest.
+type refresher struct {
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// startRefreshing starts the goroutine reloading the manifest periodically.
+func (st *Static) startRefreshing() {
+	go func() {
+		ticker := time.NewTicker(st.refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := st.ReloadManifest(); err != nil && st.refreshOnError != nil {
+					st.refreshOnError(err)
+				}
+			case <-st.refresher.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop terminates the goroutines started by WithManifestRefreshInterval and ManifestSchemePush. It can be called
+// multiple times; without these options it does nothing.
+func (st *Static) Stop() {
+	if st.refresher != nil {
+		st.refresher.stopOnce.Do(func() { close(st.refresher.stop) })
+	}
+}
@@ -0,0 +1,57 @@
+package asset
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestManifestRefreshInterval(t *testing.T) {
+	var calls int32
+	loader := func(name string) ([]byte, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil
+		}
+		return []byte(`{"js/app.js":"dist/app-5678.js"}`), nil
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader),
+		WithManifestRefreshInterval(10*time.Millisecond))
+	require.Nil(t, err)
+	defer static.Stop()
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+	require.Eventually(t, func() bool {
+		return static.URLFor("js/app.js") == "/static/dist/app-5678.js"
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestRefreshErrorHandler(t *testing.T) {
+	var calls int32
+	loader := func(name string) ([]byte, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil
+		}
+		return nil, errors.New("unavailable")
+	}
+	errs := make(chan error, 1)
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader),
+		WithManifestRefreshInterval(10*time.Millisecond),
+		WithRefreshErrorHandler(func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		}))
+	require.Nil(t, err)
+	require.Equal(t, "unavailable", (<-errs).Error())
+	static.Stop()
+	static.Stop()
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+}
+
+func TestStopWithoutRefresh(t *testing.T) {
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil))
+	require.Nil(t, err)
+	static.Stop()
+}
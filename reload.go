@@ -0,0 +1,28 @@
+package asset
+
+// WithReloadCallback can be used in NewStatic to provide a function called after every attempt to replace
+// the loaded manifest, e.g. with an update received by ManifestSchemePush: with nil on success and with
+// the error otherwise. Can be used multiple times; callbacks are called in the order they were registered.
+func WithReloadCallback(fn func(err error)) optionSetter {
+	return func(st *Static) { st.reloadCallbacks = append(st.reloadCallbacks, fn) }
+}
+
+// reloaded notifies the reload callbacks about the result of a reload attempt.
+func (st *Static) reloaded(err error) {
+	err = st.manifestError(err)
+	for _, callback := range st.reloadCallbacks {
+		callback(err)
+	}
+}
+
+// ReloadManifest builds the mapping again, e.g. loading the manifest after a deploy, and replaces the current
+// one with it. If building fails, the error is returned and the current mapping is kept. Safe to call while
+// templates are being rendered, e.g. from a SIGHUP handler. The reload callbacks are notified as well.
+func (st *Static) ReloadManifest() error {
+	mapping, err := st.mappingBuilder()
+	if err == nil {
+		st.replaceMapping(mapping)
+	}
+	st.reloaded(err)
+	return st.manifestError(err)
+}
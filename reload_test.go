@@ -0,0 +1,147 @@
+package asset
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestReloadCallback(t *testing.T) {
+	updates := make(chan []byte)
+	results := make(chan string, 4)
+	static, err := NewStatic(
+		"/static", "manifest.json", WithManifestLoader(nil),
+		WithManifestScheme(ManifestSchemePush), WithManifestUpdateChan(updates),
+		WithReloadCallback(func(err error) { results <- "first " + errString(err) }),
+		WithReloadCallback(func(err error) { results <- "second " + errString(err) }),
+	)
+	require.Nil(t, err)
+
+	updates <- []byte(`["js/app.js"]`)
+	require.Equal(t, "first "+errManifestNotObject.Error(), <-results)
+	require.Equal(t, "second "+errManifestNotObject.Error(), <-results)
+	updates <- []byte(`{"js/app.js":"dist/app-1234.js"}`)
+	require.Equal(t, "first <nil>", <-results)
+	require.Equal(t, "second <nil>", <-results)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+	close(updates)
+}
+
+func errString(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	return err.Error()
+}
+
+func TestReloadManifest(t *testing.T) {
+	content := []byte(`{"js/app.js":"dist/app-1234.js"}`)
+	var mu sync.Mutex
+	loader := func(name string) ([]byte, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return content, nil
+	}
+	results := make(chan error, 2)
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader),
+		WithReloadCallback(func(err error) { results <- err }))
+	require.Nil(t, err)
+
+	mu.Lock()
+	content = []byte(`["js/app.js"]`)
+	mu.Unlock()
+	require.True(t, errors.Is(static.ReloadManifest(), ErrInvalidManifest))
+	require.NotNil(t, <-results)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+
+	mu.Lock()
+	content = []byte(`{"js/app.js":"dist/app-5678.js"}`)
+	mu.Unlock()
+	require.Nil(t, static.Clone().ReloadManifest())
+	require.Nil(t, <-results)
+	require.Equal(t, "/static/dist/app-5678.js", static.URLFor("js/app.js"))
+}
+
+func TestReloadManifestConcurrent(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- renderConcurrently(static)
+		}()
+	}
+	for i := 0; i < 50; i++ {
+		static.SwapManifest(mapperFunc(func(name string) string { return "dist/" + name }))
+		require.Nil(t, static.ReloadManifest())
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.Nil(t, err)
+	}
+}
+
+// renderConcurrently uses static like templates rendered while the manifest is replaced.
+func renderConcurrently(static *Static) error {
+	for i := 0; i < 100; i++ {
+		tag, err := static.ScriptTag("js/app.js")
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(string(tag), `src="/static/dist/`) {
+			return fmt.Errorf("unexpected tag %s", tag)
+		}
+		if _, err := static.LinkTag("css/style.css"); err != nil {
+			return err
+		}
+		if static.Static() != "/static/" {
+			return fmt.Errorf("unexpected prefix %s", static.Static())
+		}
+		if len(static.FuncMap()) == 0 {
+			return errors.New("empty FuncMap")
+		}
+	}
+	return nil
+}
+
+func TestInitialManifest(t *testing.T) {
+	loaded := false
+	loader := func(name string) ([]byte, error) {
+		loaded = true
+		return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil
+	}
+	initial := mapperFunc(func(name string) string { return "initial/" + name })
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithInitialManifest(initial))
+	require.Nil(t, err)
+	require.False(t, loaded)
+	require.Equal(t, "/static/initial/js/app.js", static.URLFor("js/app.js"))
+	require.Nil(t, static.ReloadManifest())
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+}
+
+func TestKeys(t *testing.T) {
+	content := []byte(`{"js/app.js":"dist/app-1234.js","css/style.css":"dist/style-1234.css","a.js":"a-1234.js"}`)
+	var mu sync.Mutex
+	loader := func(name string) ([]byte, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return content, nil
+	}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	require.Equal(t, []string{"a.js", "css/style.css", "js/app.js"}, static.Keys())
+
+	mu.Lock()
+	content = []byte(`{"js/new.js":"dist/new-1234.js"}`)
+	mu.Unlock()
+	require.Nil(t, static.ReloadManifest())
+	require.Equal(t, []string{"js/new.js"}, static.Keys())
+}
@@ -0,0 +1,29 @@
+// Package schema adds JSON Schema validation of manifests to go-asset-helper, using santhosh-tekuri/jsonschema.
+package schema
+
+import (
+	"bytes"
+	"github.com/rsniezynski/go-asset-helper"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+const schemaURL = "manifest.schema.json"
+
+// WithManifestSchemaValidation can be used in asset.NewStatic to validate manifests against the JSON Schema
+// schema, e.g. to require keys or limit the number of entries. The schema itself is checked against its
+// meta-schema; an invalid schema, like an invalid manifest, makes NewStatic return an error.
+func WithManifestSchemaValidation(schema []byte) func(*asset.Static) {
+	compiled, err := compile(schema)
+	if err != nil {
+		return asset.WithOptionError(err)
+	}
+	return asset.WithManifestValidator(compiled.Validate)
+}
+
+func compile(schema []byte) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(schemaURL, bytes.NewReader(schema)); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(schemaURL)
+}
@@ -0,0 +1,38 @@
+package schema
+
+import (
+	"github.com/rsniezynski/go-asset-helper"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const manifestSchema = `{
+	"type": "object",
+	"required": ["js/app.js"],
+	"maxProperties": 2,
+	"additionalProperties": {"type": "string", "pattern": "-[0-9a-f]{4}\\."}
+}`
+
+func TestWithManifestSchemaValidation(t *testing.T) {
+	for content, valid := range map[string]bool{
+		`{"js/app.js":"dist/app-1234.js"}`:                                       true,
+		`{"css/style.css":"dist/style-1234.css"}`:                                false,
+		`{"js/app.js":"dist/app.js"}`:                                            false,
+		`{"js/app.js":"dist/app-1234.js","a.js":"a-1234.js","b.js":"b-1234.js"}`: false,
+		`{"js/app.js":{"file":"dist/app-1234.js"}}`:                              false,
+	} {
+		content := content
+		loader := func(name string) ([]byte, error) { return []byte(content), nil }
+		_, err := asset.NewStatic("/static", "manifest.json", asset.WithManifestLoader(loader),
+			WithManifestSchemaValidation([]byte(manifestSchema)))
+		require.Equal(t, valid, err == nil, content)
+	}
+}
+
+func TestWithManifestSchemaValidationInvalidSchema(t *testing.T) {
+	for _, schema := range []string{`{"type": 12}`, `not json`} {
+		_, err := asset.NewStatic("/static", "manifest.json", asset.WithManifestLoader(nil),
+			WithManifestSchemaValidation([]byte(schema)))
+		require.NotNil(t, err, schema)
+	}
+}
@@ -0,0 +1,49 @@
+package asset
+
+import (
+	"io/fs"
+	"net/url"
+	"sort"
+)
+
+// SitemapURL is an entry of an XML sitemap.
+type SitemapURL struct {
+	Loc     string
+	LastMod string
+}
+
+// GenerateSitemapEntries returns sitemap entries for all the assets in the manifest, sorted by manifest key.
+// Asset URLs are resolved against base, e.g. "https://example.com". When the file system is given with
+// WithFS, LastMod is set to the modification date of the asset file, if available.
+func (st *Static) GenerateSitemapEntries(base string) ([]SitemapURL, error) {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	mapping, ok := st.currentMapping().(*staticMap)
+	if !ok {
+		return nil, errManifestUnavailable
+	}
+	keys := append([]string{}, mapping.keys()...)
+	sort.Strings(keys)
+	entries := []SitemapURL{}
+	seen := map[string]bool{}
+	for _, key := range keys {
+		assetURL, err := url.Parse(st.URLFor(key))
+		if err != nil {
+			return nil, err
+		}
+		entry := SitemapURL{Loc: baseURL.ResolveReference(assetURL).String()}
+		if seen[entry.Loc] {
+			continue
+		}
+		seen[entry.Loc] = true
+		if st.fsys != nil {
+			if info, err := fs.Stat(st.fsys, stripQuery(mapping.Get(key))); err == nil {
+				entry.LastMod = info.ModTime().UTC().Format("2006-01-02")
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,41 @@
+package asset
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestGenerateSitemapEntries(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"dist/app-1234.js", "css/style.css":"dist/style-1234.css", "js/app.min.js":"dist/app-1234.js", "broken": 1}`), nil
+	}
+	modTime := time.Date(2020, 5, 17, 23, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{"dist/app-1234.js": {ModTime: modTime}}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithFS(fsys))
+	require.Nil(t, err)
+	entries, err := static.GenerateSitemapEntries("https://example.com/app/")
+	require.Nil(t, err)
+	require.Equal(t, []SitemapURL{
+		{Loc: "https://example.com/static/dist/style-1234.css"},
+		{Loc: "https://example.com/static/dist/app-1234.js", LastMod: "2020-05-17"},
+	}, entries)
+
+	static, err = NewStatic("https://cdn.example.com/", "manifest.json", WithManifestLoader(loader))
+	require.Nil(t, err)
+	entries, err = static.GenerateSitemapEntries("https://example.com")
+	require.Nil(t, err)
+	require.Equal(t, SitemapURL{Loc: "https://cdn.example.com/dist/style-1234.css"}, entries[0])
+	_, err = static.GenerateSitemapEntries(":")
+	require.NotNil(t, err)
+
+	loader = func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js?v=2"}`), nil }
+	static, err = NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithFS(fsys))
+	require.Nil(t, err)
+	entries, err = static.GenerateSitemapEntries("https://example.com")
+	require.Nil(t, err)
+	require.Equal(t, []SitemapURL{
+		{Loc: "https://example.com/static/dist/app-1234.js?v=2", LastMod: "2020-05-17"},
+	}, entries)
+}
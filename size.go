@@ -0,0 +1,55 @@
+package asset
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+// LimitAction selects what happens when an asset exceeds the limit set with WithAssetSizeLimit.
+type LimitAction int
+
+const (
+	// LimitActionWarn passes the error to the function given with WithOnError. This is the default.
+	LimitActionWarn LimitAction = iota
+	// LimitActionError returns the error from AssetSize and ValidateAllSRI.
+	LimitActionError
+)
+
+// WithAssetSizeLimit can be used in NewStatic to detect bundle size regressions: assets larger than maxBytes
+// are reported by AssetSize and ValidateAllSRI as set with WithAssetSizeLimitAction. There's no limit by default.
+func WithAssetSizeLimit(maxBytes int64) optionSetter {
+	return func(st *Static) { st.sizeLimit = maxBytes }
+}
+
+// WithAssetSizeLimitAction can be used together with WithAssetSizeLimit to select how assets exceeding
+// the limit are reported.
+func WithAssetSizeLimitAction(action LimitAction) optionSetter {
+	return func(st *Static) { st.sizeLimitAction = action }
+}
+
+// AssetSize returns the size of the asset file the path is resolved to. Files are read from the file system
+// given with WithFS, or relative to the current directory by default.
+func (st *Static) AssetSize(path string) (int64, error) {
+	file := stripQuery(st.resolve(path))
+	info, err := fs.Stat(st.assetFS(), file)
+	if err != nil {
+		return 0, st.assetError(path, err)
+	}
+	if err := st.checkSize(file, info.Size()); err != nil {
+		return info.Size(), st.assetError(path, err)
+	}
+	return info.Size(), nil
+}
+
+// checkSize reports a file exceeding the size limit; the error is returned only with LimitActionError.
+func (st *Static) checkSize(file string, size int64) error {
+	if st.sizeLimit <= 0 || size <= st.sizeLimit {
+		return nil
+	}
+	err := fmt.Errorf("%s is %d bytes, over the limit of %d bytes", file, size, st.sizeLimit)
+	if st.sizeLimitAction == LimitActionError {
+		return err
+	}
+	st.reportError(err)
+	return nil
+}
@@ -0,0 +1,64 @@
+package asset
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAssetSize(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	fsys := fstest.MapFS{"dist/app-1234.js": {Data: []byte("alert(1)")}}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithFS(fsys))
+	require.Nil(t, err)
+	size, err := static.AssetSize("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, int64(8), size)
+	_, err = static.AssetSize("js/other.js")
+	require.NotNil(t, err)
+
+	static, err = NewStatic("/static", "", WithManifestLoader(nil), WithFS(fsys), WithAssetHashing(nil))
+	require.Nil(t, err)
+	size, err = static.AssetSize("dist/app-1234.js")
+	require.Nil(t, err)
+	require.Equal(t, int64(8), size)
+}
+
+func TestAssetSizeLimitWarn(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	fsys := fstest.MapFS{"dist/app-1234.js": {Data: []byte("alert(1)")}}
+	var warnings []error
+	static, err := NewStatic(
+		"/static", "manifest.json", WithManifestLoader(loader), WithFS(fsys),
+		WithAssetSizeLimit(4), WithOnError(func(err error) { warnings = append(warnings, err) }),
+	)
+	require.Nil(t, err)
+	size, err := static.AssetSize("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, int64(8), size)
+	require.Nil(t, static.ValidateAllSRI())
+	require.Len(t, warnings, 2)
+	require.Equal(t, "dist/app-1234.js is 8 bytes, over the limit of 4 bytes", warnings[0].Error())
+}
+
+func TestAssetSizeLimitError(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app-1234.js"}`), nil }
+	fsys := fstest.MapFS{"dist/app-1234.js": {Data: []byte("alert(1)")}}
+	static, err := NewStatic(
+		"/static", "manifest.json", WithManifestLoader(loader), WithFS(fsys),
+		WithAssetSizeLimit(4), WithAssetSizeLimitAction(LimitActionError),
+	)
+	require.Nil(t, err)
+	_, err = static.AssetSize("js/app.js")
+	require.NotNil(t, err)
+	err = static.ValidateAllSRI()
+	require.Len(t, err, 1)
+
+	static, err = NewStatic(
+		"/static", "manifest.json", WithManifestLoader(loader), WithFS(fsys),
+		WithAssetSizeLimit(8), WithAssetSizeLimitAction(LimitActionError),
+	)
+	require.Nil(t, err)
+	_, err = static.AssetSize("js/app.js")
+	require.Nil(t, err)
+}
@@ -0,0 +1,197 @@
+package asset
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"io/fs"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const defaultSriAlgorithm = "sha256"
+
+// WithUseSri can be used in NewStatic to add subresource integrity hashes to the tags emitted by ScriptTag
+// and LinkTag, together with crossorigin="anonymous" (which can be overridden with attrs). Hashes are computed
+// from the asset files the paths are resolved to, read from the file system given with WithFS (the current
+// directory by default) regardless of the URL prefix, and cached. If an asset file can't be read, ScriptTag
+// and LinkTag return an error.
+func WithUseSri(enabled bool) optionSetter {
+	return func(st *Static) { st.useSri = enabled }
+}
+
+// WithSriAlgorithm can be used in NewStatic to select the hash algorithm used with WithUseSri: "sha256"
+// (the default), "sha384" or "sha512". An unsupported algorithm makes NewStatic return an error.
+func WithSriAlgorithm(algo string) optionSetter {
+	return WithSriAlgorithms(algo)
+}
+
+// WithSriAlgorithms can be used in NewStatic to include hashes computed with multiple algorithms in
+// the integrity attributes, separated by spaces. See WithSriAlgorithm.
+func WithSriAlgorithms(algos ...string) optionSetter {
+	return func(st *Static) {
+		if len(algos) == 0 {
+			st.setOptionErr(errors.New("no SRI algorithm given"))
+			return
+		}
+		for _, algo := range algos {
+			if _, ok := sriHashes[algo]; !ok {
+				st.setOptionErr(fmt.Errorf("unsupported SRI algorithm %q", algo))
+				return
+			}
+		}
+		st.sriAlgorithms = algos
+	}
+}
+
+// addSRI adds integrity and crossorigin attributes for the resolved asset if SRI is enabled.
+func (st *Static) addSRI(attrMap map[string]string, resolved string) error {
+	if !st.useSri {
+		return nil
+	}
+	hash, err := st.sri(stripQuery(resolved))
+	if err != nil {
+		return err
+	}
+	attrMap["integrity"] = hash
+	attrMap["crossorigin"] = "anonymous"
+	return nil
+}
+
+// sri returns the cached SRI hash of a file, computing it if needed.
+func (st *Static) sri(file string) (string, error) {
+	if hash, ok := st.sriCache.Load(st.sriCacheKey(file)); ok {
+		return hash.(string), nil
+	}
+	hash, err := computeSRI(st.assetFS(), file, st.sriAlgorithms...)
+	if err != nil {
+		return "", err
+	}
+	st.sriCache.Store(st.sriCacheKey(file), hash)
+	return hash, nil
+}
+
+// sriCacheKey returns the key of the SRI hash of file in the cache, which is shared with clones that may use
+// other algorithms, e.g. set with WithRequestOptions.
+func (st *Static) sriCacheKey(file string) string {
+	return strings.Join(st.sriAlgorithms, " ") + ":" + file
+}
+
+// WithConcurrentSRIComputation can be used in NewStatic to compute SRI hashes in ValidateAllSRI using
+// workers goroutines. workers is capped at runtime.NumCPU(); exceeding it is reported to the function
+// given with WithOnError. Hashes are computed sequentially by default.
+func WithConcurrentSRIComputation(workers int) optionSetter {
+	return func(st *Static) { st.sriWorkers = workers }
+}
+
+// ValidateAllSRI computes the SRI hash of every asset file referenced in the manifest values and returns
+// MultiError listing all the files that can't be read, or exceed the limit set with WithAssetSizeLimit
+// with LimitActionError. Files are read from the file system given with WithFS, or relative to the current
+// directory by default. Useful as a startup health check; computed hashes are cached.
+func (st *Static) ValidateAllSRI() error {
+	files, err := st.manifestFiles()
+	if err != nil {
+		return err
+	}
+	var errs MultiError
+	for i, err := range st.computeAllSRI(files) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		file := files[i]
+		if st.sizeLimit > 0 {
+			info, err := fs.Stat(st.assetFS(), file)
+			if err == nil {
+				err = st.checkSize(file, info.Size())
+			}
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// computeAllSRI computes and caches SRI hashes of files, returning the error for every file.
+func (st *Static) computeAllSRI(files []string) []error {
+	errs := make([]error, len(files))
+	compute := func(i int) {
+		hash, err := computeSRI(st.assetFS(), files[i], st.sriAlgorithms...)
+		if err != nil {
+			errs[i] = err
+			return
+		}
+		st.sriCache.Store(st.sriCacheKey(files[i]), hash)
+	}
+	workers := st.sriWorkers
+	if workers > runtime.NumCPU() {
+		st.reportError(fmt.Errorf("%d SRI workers requested, using runtime.NumCPU() = %d", workers, runtime.NumCPU()))
+		workers = runtime.NumCPU()
+	}
+	if workers <= 1 {
+		for i := range files {
+			compute(i)
+		}
+		return errs
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				compute(i)
+			}
+		}()
+	}
+	for i := range files {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return errs
+}
+
+// assetFS returns the file system asset files are read from.
+func (st *Static) assetFS() fs.FS {
+	if st.fsys != nil {
+		return st.fsys
+	}
+	return os.DirFS(".")
+}
+
+// sriHashes maps supported SRI algorithms to hash constructors.
+var sriHashes = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha384": sha512.New384,
+	"sha512": sha512.New,
+}
+
+// computeSRI returns the subresource integrity value of a file: space separated hashes computed with every
+// algorithm, sha256 if none is given.
+func computeSRI(fsys fs.FS, name string, algorithms ...string) (string, error) {
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return "", err
+	}
+	if len(algorithms) == 0 {
+		algorithms = []string{defaultSriAlgorithm}
+	}
+	hashes := make([]string, len(algorithms))
+	for i, algorithm := range algorithms {
+		h := sriHashes[algorithm]()
+		h.Write(content)
+		hashes[i] = fmt.Sprintf("%s-%s", algorithm, base64.StdEncoding.EncodeToString(h.Sum(nil)))
+	}
+	return strings.Join(hashes, " "), nil
+}
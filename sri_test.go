@@ -0,0 +1,149 @@
+package asset
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"html/template"
+	"io/fs"
+	"runtime"
+	"testing"
+	"testing/fstest"
+)
+
+func TestComputeSRI(t *testing.T) {
+	fsys := fstest.MapFS{"app.js": {Data: []byte("alert(1)")}}
+	hash, err := computeSRI(fsys, "app.js")
+	require.Nil(t, err)
+	require.Equal(t, "sha256-bhHHL3z2vDgxUt0W3dWQOrprscmda2Y5pLsLg4GF+pI=", hash)
+}
+
+func TestValidateAllSRI(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"dist/app.js", "js/other.js":"dist/other.js", "css/style.css":"dist/style.css"}`), nil
+	}
+	fsys := fstest.MapFS{"dist/app.js": {Data: []byte("alert(1)")}}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithFS(fsys))
+	require.Nil(t, err)
+	err = static.ValidateAllSRI()
+	errs, ok := err.(MultiError)
+	require.True(t, ok)
+	require.Len(t, errs, 2)
+	require.Contains(t, errs[0].Error(), "dist/other.js")
+	require.Contains(t, errs[1].Error(), "dist/style.css")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	fsys["dist/other.js"] = &fstest.MapFile{}
+	fsys["dist/style.css"] = &fstest.MapFile{}
+	require.Nil(t, static.ValidateAllSRI())
+}
+
+func TestConcurrentSRIComputation(t *testing.T) {
+	manifest := map[string]string{}
+	fsys := fstest.MapFS{}
+	for i := 0; i < 50; i++ {
+		name := fmt.Sprintf("dist/app%d.js", i)
+		manifest[fmt.Sprintf("js/app%d.js", i)] = name
+		if i%10 != 0 {
+			fsys[name] = &fstest.MapFile{Data: []byte(name)}
+		}
+	}
+	content, err := json.Marshal(manifest)
+	require.Nil(t, err)
+	loader := func(name string) ([]byte, error) { return content, nil }
+	var warnings []error
+	static, err := NewStatic(
+		"/static", "manifest.json", WithManifestLoader(loader), WithFS(fsys),
+		WithConcurrentSRIComputation(runtime.NumCPU()+1),
+		WithOnError(func(err error) { warnings = append(warnings, err) }),
+	)
+	require.Nil(t, err)
+	err = static.ValidateAllSRI()
+	require.Len(t, warnings, 1)
+	require.Len(t, err, 5)
+	require.Contains(t, err.(MultiError)[0].Error(), "dist/app0.js")
+	hash, ok := static.sriCache.Load(static.sriCacheKey("dist/app1.js"))
+	require.True(t, ok)
+	expected, err := computeSRI(fsys, "dist/app1.js")
+	require.Nil(t, err)
+	require.Equal(t, expected, hash)
+}
+
+func TestUseSri(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"dist/app-1234.js", "css/style.css":"dist/style-1234.css?v=1"}`), nil
+	}
+	fsys := fstest.MapFS{
+		"dist/app-1234.js":    {Data: []byte("alert(1)")},
+		"dist/style-1234.css": {Data: []byte("body {}")},
+	}
+	static, err := NewStatic(
+		"https://cdn.example.com/", "manifest.json", WithManifestLoader(loader), WithFS(fsys), WithUseSri(true),
+	)
+	require.Nil(t, err)
+	tag, err := static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<script crossorigin="anonymous" integrity="sha256-bhHHL3z2vDgxUt0W3dWQOrprscmda2Y5pLsLg4GF+pI=" `+
+			`src="https://cdn.example.com/dist/app-1234.js" type="text/javascript"></script>`,
+	), tag)
+	styleHash, err := computeSRI(fsys, "dist/style-1234.css")
+	require.Nil(t, err)
+	tag, err = static.LinkTag("css/style.css", "crossorigin", "use-credentials")
+	require.Nil(t, err)
+	require.Equal(t, template.HTML(
+		`<link crossorigin="use-credentials" href="https://cdn.example.com/dist/style-1234.css?v=1" `+
+			`integrity="`+styleHash+`" rel="stylesheet" type="text/css"/>`,
+	), tag)
+	_, err = static.ScriptTag("js/missing.js")
+	require.NotNil(t, err)
+	_, err = static.LinkTag("css/missing.css")
+	require.NotNil(t, err)
+}
+
+func TestUseSriCached(t *testing.T) {
+	fsys := fstest.MapFS{"js/app.js": {Data: []byte("alert(1)")}}
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithFS(fsys), WithUseSri(true))
+	require.Nil(t, err)
+	first, err := static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	delete(fsys, "js/app.js")
+	second, err := static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Equal(t, first, second)
+}
+
+func TestSriAlgorithm(t *testing.T) {
+	fsys := fstest.MapFS{"js/app.js": {Data: []byte("alert(1)")}}
+	static, err := NewStatic(
+		"/static", "manifest.json", WithManifestLoader(nil), WithFS(fsys), WithUseSri(true), WithSriAlgorithm("sha384"),
+	)
+	require.Nil(t, err)
+	tag, err := static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Contains(t, tag, `integrity="sha384-`)
+
+	static, err = NewStatic(
+		"/static", "manifest.json", WithManifestLoader(nil), WithFS(fsys),
+		WithUseSri(true), WithSriAlgorithms("sha256", "sha512"),
+	)
+	require.Nil(t, err)
+	tag, err = static.ScriptTag("js/app.js")
+	require.Nil(t, err)
+	require.Contains(t, tag, `integrity="sha256-bhHHL3z2vDgxUt0W3dWQOrprscmda2Y5pLsLg4GF+pI= sha512-`)
+}
+
+func TestSriAlgorithmUnsupported(t *testing.T) {
+	_, err := NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithSriAlgorithm("md5"))
+	require.NotNil(t, err)
+	_, err = NewStatic("/static", "manifest.json", WithManifestLoader(nil), WithSriAlgorithms())
+	require.NotNil(t, err)
+}
+
+func TestComputeSRIAlgorithms(t *testing.T) {
+	fsys := fstest.MapFS{"app.js": {Data: []byte("alert(1)")}}
+	hash, err := computeSRI(fsys, "app.js", "sha384")
+	require.Nil(t, err)
+	require.Equal(t, "sha384-", hash[:7])
+	require.Len(t, hash, 7+64)
+}
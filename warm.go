@@ -0,0 +1,55 @@
+package asset
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"io/ioutil"
+)
+
+// WithWarmCacheProgress can be used in NewStatic to provide a function called by WarmCache after every file,
+// with the number of files read so far and the total number of files.
+func WithWarmCacheProgress(fn func(done, total int)) optionSetter {
+	return func(st *Static) { st.warmProgress = fn }
+}
+
+// WarmCache reads all the asset files referenced in the manifest values, discarding their content, to get
+// them into the OS file cache before serving traffic. Files are read from the file system given with WithFS,
+// or relative to the current directory by default. Returns MultiError listing the files that can't be read,
+// or the context error if ctx is done before all the files are read.
+func (st *Static) WarmCache(ctx context.Context) error {
+	files, err := st.manifestFiles()
+	if err != nil {
+		return err
+	}
+	fsys := st.assetFS()
+	var errs MultiError
+	for i, file := range files {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		if err := discardFile(fsys, file); err != nil {
+			errs = append(errs, err)
+		}
+		if st.warmProgress != nil {
+			st.warmProgress(i+1, len(files))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// discardFile reads a file, discarding its content.
+func discardFile(fsys fs.FS, name string) error {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(ioutil.Discard, file)
+	return err
+}
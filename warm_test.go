@@ -0,0 +1,37 @@
+package asset
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWarmCache(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"js/app.js":"dist/app.js", "js/app.min.js":"dist/app.js", "js/other.js":"dist/other.js?v=1", "css/style.css":"dist/style.css"}`), nil
+	}
+	fsys := fstest.MapFS{"dist/app.js": {Data: []byte("alert(1)")}, "dist/other.js": {}}
+	var progress [][2]int
+	static, err := NewStatic(
+		"/static", "manifest.json", WithManifestLoader(loader), WithFS(fsys),
+		WithWarmCacheProgress(func(done, total int) { progress = append(progress, [2]int{done, total}) }),
+	)
+	require.Nil(t, err)
+	err = static.WarmCache(context.Background())
+	require.Len(t, err, 1)
+	require.Contains(t, err.Error(), "dist/style.css")
+	require.Equal(t, [][2]int{{1, 3}, {2, 3}, {3, 3}}, progress)
+
+	fsys["dist/style.css"] = &fstest.MapFile{}
+	require.Nil(t, static.WarmCache(context.Background()))
+}
+
+func TestWarmCacheCanceled(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{"js/app.js":"dist/app.js"}`), nil }
+	static, err := NewStatic("/static", "manifest.json", WithManifestLoader(loader), WithFS(fstest.MapFS{}))
+	require.Nil(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Equal(t, context.Canceled, static.WarmCache(ctx))
+}
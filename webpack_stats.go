@@ -0,0 +1,100 @@
+package asset
+
+import (
+	"encoding/json"
+	"errors"
+	"path"
+	"sort"
+	"strings"
+)
+
+// WebpackStatsMapper is a StaticMapper for the stats.json of webpack (webpack --json), resolving assets
+// with the assetsByChunkName map. It's used by NewStatic with WithManifestFormat(ManifestFormatWebpackStats),
+// and can be also created with NewWebpackStatsMapper and provided with WithMappingBuilder.
+type WebpackStatsMapper struct {
+	chunks map[string][]string
+}
+
+// NewWebpackStatsMapper returns WebpackStatsMapper for the stats.json content.
+func NewWebpackStatsMapper(content []byte) (*WebpackStatsMapper, error) {
+	var stats struct {
+		AssetsByChunkName map[string]interface{} `json:"assetsByChunkName"`
+	}
+	if err := json.Unmarshal(content, &stats); err != nil {
+		return nil, &invalidManifestError{err}
+	}
+	if stats.AssetsByChunkName == nil {
+		return nil, errNoAssetsByChunkName
+	}
+	chunks := make(map[string][]string, len(stats.AssetsByChunkName))
+	for name, value := range stats.AssetsByChunkName {
+		switch files := value.(type) {
+		case string:
+			chunks[name] = []string{files}
+		case []interface{}:
+			for _, file := range files {
+				if str, ok := file.(string); ok {
+					chunks[name] = append(chunks[name], str)
+				}
+			}
+		}
+	}
+	return &WebpackStatsMapper{chunks: chunks}, nil
+}
+
+// loadWebpackStats returns WebpackStatsMapper for the stats loaded from path; with nil load it's empty.
+func loadWebpackStats(load Loader, path string) (StaticMapper, error) {
+	if load == nil {
+		return &WebpackStatsMapper{chunks: map[string][]string{}}, nil
+	}
+	content, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewWebpackStatsMapper(content)
+}
+
+// Get returns the file of a chunk: name is the chunk name, optionally followed by the extension of the
+// file, e.g. "main.css" for the first .css file of the "main" chunk. Names not found are returned as is.
+func (wm *WebpackStatsMapper) Get(name string) string {
+	if file, ok := wm.find(name); ok {
+		return file
+	}
+	return name
+}
+
+// Has reports whether there's a file for name. See Get.
+func (wm *WebpackStatsMapper) Has(name string) bool {
+	_, ok := wm.find(name)
+	return ok
+}
+
+// Keys returns the sorted chunk names.
+func (wm *WebpackStatsMapper) Keys() []string {
+	keys := make([]string, 0, len(wm.chunks))
+	for name := range wm.chunks {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (wm *WebpackStatsMapper) find(name string) (string, bool) {
+	if files := wm.chunks[name]; len(files) > 0 {
+		return files[0], true
+	}
+	ext := path.Ext(name)
+	for _, file := range wm.chunks[strings.TrimSuffix(name, ext)] {
+		if path.Ext(stripQuery(file)) == ext {
+			return file, true
+		}
+	}
+	return "", false
+}
+
+// ChunkFiles returns all the files of a chunk, e.g. to emit tags for each of them in a template.
+func (wm *WebpackStatsMapper) ChunkFiles(chunkName string) []string {
+	return wm.chunks[chunkName]
+}
+
+var errNoAssetsByChunkName error = &invalidManifestError{errors.New("webpack stats have no assetsByChunkName")}
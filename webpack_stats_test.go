@@ -0,0 +1,37 @@
+package asset
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestManifestFormatWebpackStats(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte(`{"hash": "abc", "assetsByChunkName": {
+			"main": ["main.1234.js", "main.1234.css", "main.1234.js.map"],
+			"vendor": "vendor.5678.js"
+		}}`), nil
+	}
+	static, err := NewStatic("/static", "stats.json", WithManifestLoader(loader), WithManifestFormat(ManifestFormatWebpackStats))
+	require.Nil(t, err)
+	require.Equal(t, "/static/main.1234.js", static.URLFor("main.js"))
+	require.Equal(t, "/static/main.1234.css", static.URLFor("main.css"))
+	require.Equal(t, "/static/main.1234.js", static.URLFor("main"))
+	require.Equal(t, "/static/vendor.5678.js", static.URLFor("vendor.js"))
+	require.Equal(t, "/static/other.js", static.URLFor("other.js"))
+	mapper, ok := static.currentMapping().(*WebpackStatsMapper)
+	require.True(t, ok)
+	require.Equal(t, []string{"main.1234.js", "main.1234.css", "main.1234.js.map"}, mapper.ChunkFiles("main"))
+	require.Nil(t, mapper.ChunkFiles("other"))
+	require.True(t, static.Has("main.css"))
+	require.False(t, static.Has("main.png"))
+	require.Equal(t, []string{"main", "vendor"}, static.Keys())
+}
+
+func TestWebpackStatsInvalid(t *testing.T) {
+	for _, content := range []string{"garbage", `{"hash": "abc"}`} {
+		_, err := NewWebpackStatsMapper([]byte(content))
+		require.True(t, errors.Is(err, ErrInvalidManifest), content)
+	}
+}
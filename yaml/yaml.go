@@ -0,0 +1,28 @@
+// Package yaml adds support for YAML manifests to go-asset-helper, parsed with gopkg.in/yaml.v3.
+package yaml
+
+import (
+	"github.com/rsniezynski/go-asset-helper"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// Parser is an asset.ManifestParser of YAML mappings of asset paths to file names.
+type Parser struct{}
+
+// Parse parses content as a YAML mapping. Other documents, e.g. sequences, are returned as errors.
+func (Parser) Parse(content []byte) (map[string]string, error) {
+	manifest := map[string]string{}
+	if err := yamlv3.Unmarshal(content, &manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// WithYAMLManifest can be used in asset.NewStatic to load YAML manifests, setting asset.ManifestFormatYAML
+// parsed with Parser.
+func WithYAMLManifest() func(*asset.Static) {
+	return func(st *asset.Static) {
+		asset.WithManifestFormat(asset.ManifestFormatYAML)(st)
+		asset.WithManifestParser(Parser{})(st)
+	}
+}
@@ -0,0 +1,24 @@
+package yaml
+
+import (
+	"errors"
+	"github.com/rsniezynski/go-asset-helper"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWithYAMLManifest(t *testing.T) {
+	loader := func(name string) ([]byte, error) {
+		return []byte("js/app.js: dist/app-1234.js\n\"css/style.css\": dist/style-1234.css\n"), nil
+	}
+	static, err := asset.NewStatic("/static", "manifest.yaml", asset.WithManifestLoader(loader), WithYAMLManifest())
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+	require.Equal(t, "/static/dist/style-1234.css", static.URLFor("css/style.css"))
+}
+
+func TestWithYAMLManifestInvalid(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte("- js/app.js\n"), nil }
+	_, err := asset.NewStatic("/static", "manifest.yaml", asset.WithManifestLoader(loader), WithYAMLManifest())
+	require.True(t, errors.Is(err, asset.ErrInvalidManifest))
+}
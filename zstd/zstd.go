@@ -0,0 +1,33 @@
+// Package zstd adds support for Zstandard compressed manifests to go-asset-helper, using klauspost/compress.
+package zstd
+
+import (
+	"github.com/klauspost/compress/zstd"
+	"github.com/rsniezynski/go-asset-helper"
+)
+
+// WithZstdManifest can be used in asset.NewStatic to load manifests compressed with Zstandard at the given
+// level. Decompression doesn't depend on the level, it's accepted to match Compress used to produce them.
+func WithZstdManifest(level int) func(*asset.Static) {
+	return asset.WithManifestCompression(decompress)
+}
+
+// Compress returns content compressed with Zstandard at the given level, e.g. to produce the manifest in
+// build tooling or tests. Levels follow the zstd command line, from 1 (fastest) to 22 (best compression).
+func Compress(content []byte, level int) ([]byte, error) {
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+	if err != nil {
+		return nil, err
+	}
+	defer encoder.Close()
+	return encoder.EncodeAll(content, nil), nil
+}
+
+func decompress(content []byte) ([]byte, error) {
+	decoder, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, err
+	}
+	defer decoder.Close()
+	return decoder.DecodeAll(content, nil)
+}
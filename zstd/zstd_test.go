@@ -0,0 +1,22 @@
+package zstd
+
+import (
+	"github.com/rsniezynski/go-asset-helper"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWithZstdManifest(t *testing.T) {
+	content, err := Compress([]byte(`{"js/app.js":"dist/app-1234.js"}`), 3)
+	require.Nil(t, err)
+	loader := func(name string) ([]byte, error) { return content, nil }
+	static, err := asset.NewStatic("/static", "manifest.json", asset.WithManifestLoader(loader), WithZstdManifest(3))
+	require.Nil(t, err)
+	require.Equal(t, "/static/dist/app-1234.js", static.URLFor("js/app.js"))
+}
+
+func TestWithZstdManifestInvalid(t *testing.T) {
+	loader := func(name string) ([]byte, error) { return []byte(`{}`), nil }
+	_, err := asset.NewStatic("/static", "manifest.json", asset.WithManifestLoader(loader), WithZstdManifest(3))
+	require.NotNil(t, err)
+}